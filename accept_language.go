@@ -0,0 +1,222 @@
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxAcceptLanguageRanges limits number of language ranges taken from
+// the Accept-Language header.
+const maxAcceptLanguageRanges = 32
+
+// AcceptLanguage is a weighted language range of the Accept-Language header.
+type AcceptLanguage struct {
+	// Code holds canonical language code or "*".
+	Code string
+
+	// Quality holds q-value in the range (0, 1].
+	Quality float64
+}
+
+// ParseAcceptLanguage parses value of the Accept-Language header
+// and returns language ranges sorted by quality in descending order.
+// Malformed ranges and ranges with q=0 are skipped.
+//
+// Example:
+// ParseAcceptLanguage("da, en-gb;q=0.8, en;q=0.7") returns
+// [{da 1} {en-GB 0.8} {en 0.7}]
+func ParseAcceptLanguage(header string) []AcceptLanguage {
+
+	var res []AcceptLanguage
+
+	for _, part := range strings.Split(header, ",") {
+		if len(res) == maxAcceptLanguageRanges {
+			break
+		}
+
+		params := strings.Split(part, ";")
+		code := strings.TrimSpace(params[0])
+		if code == "" {
+			continue
+		}
+
+		q, ok := 1.0, true
+		for _, p := range params[1:] {
+			name, val, found := strings.Cut(strings.TrimSpace(p), "=")
+			if !found || !strings.EqualFold(strings.TrimSpace(name), "q") {
+				continue
+			}
+			q, ok = parseQuality(strings.TrimSpace(val))
+		}
+		if !ok || q == 0 {
+			continue
+		}
+
+		if code != "*" {
+			var err error
+			if code, err = CanonicalCode(code); err != nil {
+				continue
+			}
+		}
+
+		res = append(res, AcceptLanguage{Code: code, Quality: q})
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Quality > res[j].Quality
+	})
+	return res
+}
+
+// parseQuality parses q-value like "0.8".
+func parseQuality(s string) (float64, bool) {
+	q, err := strconv.ParseFloat(s, 64)
+	if err != nil || q < 0 || q > 1 {
+		return 0, false
+	}
+	return q, true
+}
+
+// Confidence indicates how well a negotiated language matches the request.
+type Confidence int8
+
+const (
+	// ConfidenceNo means nothing matched and the default language is returned.
+	ConfidenceNo Confidence = iota
+
+	// ConfidenceLow means the language is taken from a different base
+	// language of the fallback chain, a sibling region or the wildcard.
+	ConfidenceLow
+
+	// ConfidenceHigh means the language is a more generic form of the
+	// requested one, like en for en-GB.
+	ConfidenceHigh
+
+	// ConfidenceExact means the requested language is available.
+	ConfidenceExact
+)
+
+// String implements fmt.Stringer interface.
+func (c Confidence) String() string {
+	switch c {
+	case ConfidenceLow:
+		return "Low"
+	case ConfidenceHigh:
+		return "High"
+	case ConfidenceExact:
+		return "Exact"
+	}
+	return "No"
+}
+
+// Matcher negotiates the best available language for the
+// Accept-Language header.
+type Matcher struct {
+	registry  *LanguageRegistry
+	available func(Language) bool
+	def       Language
+}
+
+// NewMatcher creates a matcher of languages of the registry r for which
+// available returns true. The language def is returned if nothing matched.
+func NewMatcher(r *LanguageRegistry, available func(Language) bool, def Language) *Matcher {
+	return &Matcher{
+		registry:  r,
+		available: available,
+		def:       def,
+	}
+}
+
+// Match returns the best available language for the value of the
+// Accept-Language header and the confidence of the match.
+//
+// Language ranges are tried in the order of quality. For every range
+// the fallback chain of the language is walked. The first range matched
+// with ConfidenceHigh or better wins, otherwise the first range matched
+// with ConfidenceLow.
+func (m *Matcher) Match(header string) (Language, Confidence) {
+
+	res, conf := m.def, ConfidenceNo
+
+	for _, al := range ParseAcceptLanguage(header) {
+		li, c := m.matchRange(al.Code)
+		if c >= ConfidenceHigh {
+			return li, c
+		}
+		if c > conf {
+			res, conf = li, c
+		}
+	}
+
+	return res, conf
+}
+
+// matchRange matches a single canonical language code.
+func (m *Matcher) matchRange(code string) (Language, Confidence) {
+
+	if code == "*" {
+		if m.def != Unknown && m.available(m.def) {
+			return m.def, ConfidenceLow
+		}
+		return Unknown, ConfidenceNo
+	}
+
+	base := baseCode(code)
+
+	// requested language or its registered ancestor.
+	li, c := m.registry.Lookup(code), ConfidenceExact
+	for li == Unknown {
+		parent, ok := parentCode(code)
+		if !ok {
+			break
+		}
+		code, c = parent, ConfidenceHigh
+		li = m.registry.Lookup(code)
+	}
+
+	for i, l := range m.registry.chain(li) {
+		if !m.available(l) {
+			continue
+		}
+		if i == 0 {
+			return l, c
+		}
+		if baseCode(m.registry.Code(l)) == base {
+			return l, ConfidenceHigh
+		}
+		return l, ConfidenceLow
+	}
+
+	// a sibling with the same base language, like en-US for en-GB.
+	for i, lc := range m.registry.LanguageCodes() {
+		if baseCode(lc) == base && m.available(Language(i)) {
+			return Language(i), ConfidenceLow
+		}
+	}
+
+	return Unknown, ConfidenceNo
+}
+
+// baseCode returns the primary language subtag.
+func baseCode(code string) string {
+	if pos := strings.IndexByte(code, '-'); pos != -1 {
+		return code[:pos]
+	}
+	return code
+}
+
+// Match returns the best language of the String for the value of
+// the Accept-Language header. Returns Unknown if nothing matched.
+// Languages are taken from the default registry.
+func (n String) Match(header string) (Language, Confidence) {
+	return n.MatchIn(defaultRegistry, header)
+}
+
+// MatchIn is like Match, languages are taken from the registry r.
+func (n String) MatchIn(r *LanguageRegistry, header string) (Language, Confidence) {
+	available := func(li Language) bool {
+		return li >= 0 && int(li) < len(n) && n[li] != emptyString
+	}
+	return NewMatcher(r, available, Unknown).Match(header)
+}
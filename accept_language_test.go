@@ -0,0 +1,109 @@
+package i18n
+
+import (
+	"testing"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+
+	tests := []struct {
+		header   string
+		expected []AcceptLanguage
+	}{
+		{"", nil},
+		{"da, en-gb;q=0.8, en;q=0.7", []AcceptLanguage{{"da", 1}, {"en-GB", 0.8}, {"en", 0.7}}},
+		{"en;q=0.5, fr_CA", []AcceptLanguage{{"fr-CA", 1}, {"en", 0.5}}},
+		{"de;q=0, cs, *;q=0.1", []AcceptLanguage{{"cs", 1}, {"*", 0.1}}},
+		{"en;q=2, !!, sk;q=abc, pl ; Q=0.3", []AcceptLanguage{{"pl", 0.3}}},
+	}
+
+	for _, tt := range tests {
+		got := ParseAcceptLanguage(tt.header)
+		if len(got) != len(tt.expected) {
+			t.Errorf("%q: expected %v, got %v", tt.header, tt.expected, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.expected[i] {
+				t.Errorf("%q: expected %v, got %v", tt.header, tt.expected, got)
+				break
+			}
+		}
+	}
+}
+
+func TestContainer_Match(t *testing.T) {
+	r := NewLanguageRegistry()
+	en := r.Parse("en")
+	tc := newTestContainer(t, r, WithPrimaryLanguage(en))
+
+	de := r.Lookup("de")
+	enGB := r.Lookup("en-GB")
+	enUS := r.Lookup("en-US")
+
+	tests := []struct {
+		header string
+		lang   Language
+		conf   Confidence
+	}{
+		{"de-AT, en;q=0.5", de, ConfidenceHigh},
+		{"en-gb", enGB, ConfidenceExact},
+		{"en-AU;q=0.9, de;q=0.8", en, ConfidenceHigh},
+		{"fr, *;q=0.1", en, ConfidenceLow},
+		{"fr", en, ConfidenceNo},
+		{"en-US-u-ca-gregory", enUS, ConfidenceHigh},
+		{"", en, ConfidenceNo},
+	}
+
+	for _, tt := range tests {
+		li, conf := tc.MatchConfidence(tt.header)
+		if li != tt.lang || conf != tt.conf {
+			t.Errorf("%q: expected %s/%s, got %s/%s", tt.header, r.Code(tt.lang), tt.conf, r.Code(li), conf)
+		}
+	}
+
+	if got := tc.Match("sk, de;q=0.2"); got != de {
+		t.Errorf("expected de, got %s", r.Code(got))
+	}
+}
+
+func TestMatcher_CrossLanguage(t *testing.T) {
+	r := NewLanguageRegistry()
+	hr := r.Parse("hr")
+	sr := r.Parse("sr")
+	if err := r.SetFallback(r.Parse("sr-Latn"), sr, hr); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	de := r.Parse("de")
+	enUS := r.Parse("en-US")
+
+	available := map[Language]bool{hr: true, de: true, enUS: true}
+	m := NewMatcher(r, func(li Language) bool { return available[li] }, Unknown)
+
+	if li, conf := m.Match("sr-Latn, de;q=0.5"); li != de || conf != ConfidenceExact {
+		t.Errorf("expected de/Exact, got %s/%s", r.Code(li), conf)
+	}
+	if li, conf := m.Match("sr-Latn"); li != hr || conf != ConfidenceLow {
+		t.Errorf("expected hr/Low, got %s/%s", r.Code(li), conf)
+	}
+	if li, conf := m.Match("en-GB"); li != enUS || conf != ConfidenceLow {
+		t.Errorf("expected en-US/Low, got %s/%s", r.Code(li), conf)
+	}
+}
+
+func TestString_Match(t *testing.T) {
+	r := NewLanguageRegistry()
+	n, err := r.ToString([]byte(`{"en":"Hello","cs":"Ahoj"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	li, conf := n.MatchIn(r, "cs-CZ, en;q=0.8")
+	if li != r.Lookup("cs") || conf != ConfidenceHigh {
+		t.Errorf("expected cs/High, got %s/%s", r.Code(li), conf)
+	}
+
+	if li, conf := n.MatchIn(r, "de"); li != Unknown || conf != ConfidenceNo {
+		t.Errorf("expected Unknown/No, got %d/%s", li, conf)
+	}
+}
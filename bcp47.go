@@ -0,0 +1,195 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidLanguageTag is returned if a language code is not
+// a well-formed BCP 47 language tag.
+var ErrInvalidLanguageTag = errors.New("invalid language tag")
+
+// CanonicalCode validates the BCP 47 language tag and returns it in
+// canonical form: language and extensions in lower case, script in
+// title case, region in upper case. Underscores are accepted as
+// subtag separators.
+//
+// Example:
+// CanonicalCode("EN_us") returns "en-US"
+// CanonicalCode("zh-hans-cn") returns "zh-Hans-CN"
+// CanonicalCode("sr-latn-RS-u-NU-latn") returns "sr-Latn-RS-u-nu-latn"
+func CanonicalCode(code string) (string, error) {
+
+	if code == "" {
+		return "", fmt.Errorf("%w: empty", ErrInvalidLanguageTag)
+	}
+
+	subtags := strings.Split(strings.ReplaceAll(code, "_", "-"), "-")
+	for _, s := range subtags {
+		if len(s) == 0 || len(s) > 8 || !isAlphaNum(s) {
+			return "", fmt.Errorf("%w: %q: malformed subtag %q", ErrInvalidLanguageTag, code, s)
+		}
+	}
+
+	res := make([]string, 0, len(subtags))
+	i := 0
+
+	// private use only tag, like x-whatever.
+	if strings.EqualFold(subtags[0], "x") {
+		if err := checkPrivateUse(subtags); err != nil {
+			return "", fmt.Errorf("%w: %q: %s", ErrInvalidLanguageTag, code, err)
+		}
+		return strings.ToLower(strings.Join(subtags, "-")), nil
+	}
+
+	// language
+	lang := subtags[i]
+	if len(lang) < 2 || !isAlpha(lang) {
+		return "", fmt.Errorf("%w: %q: invalid language subtag %q", ErrInvalidLanguageTag, code, lang)
+	}
+	res = append(res, strings.ToLower(lang))
+	i++
+
+	// extended language subtags, only after 2-3 letter language.
+	if len(lang) <= 3 {
+		for n := 0; n < 3 && i < len(subtags) && len(subtags[i]) == 3 && isAlpha(subtags[i]); n++ {
+			res = append(res, strings.ToLower(subtags[i]))
+			i++
+		}
+	}
+
+	// script
+	if i < len(subtags) && len(subtags[i]) == 4 && isAlpha(subtags[i]) {
+		res = append(res, strings.ToUpper(subtags[i][:1])+strings.ToLower(subtags[i][1:]))
+		i++
+	}
+
+	// region
+	if i < len(subtags) {
+		s := subtags[i]
+		if len(s) == 2 && isAlpha(s) {
+			res = append(res, strings.ToUpper(s))
+			i++
+		} else if len(s) == 3 && isDigit(s) {
+			res = append(res, s)
+			i++
+		}
+	}
+
+	// variants
+	variants := make(map[string]struct{})
+	for i < len(subtags) && isVariant(subtags[i]) {
+		v := strings.ToLower(subtags[i])
+		if _, ok := variants[v]; ok {
+			return "", fmt.Errorf("%w: %q: duplicate variant %q", ErrInvalidLanguageTag, code, v)
+		}
+		variants[v] = struct{}{}
+		res = append(res, v)
+		i++
+	}
+
+	// extensions
+	singletons := make(map[string]struct{})
+	for i < len(subtags) && len(subtags[i]) == 1 && !strings.EqualFold(subtags[i], "x") {
+		singleton := strings.ToLower(subtags[i])
+		if _, ok := singletons[singleton]; ok {
+			return "", fmt.Errorf("%w: %q: duplicate extension %q", ErrInvalidLanguageTag, code, singleton)
+		}
+		singletons[singleton] = struct{}{}
+		res = append(res, singleton)
+		i++
+
+		n := 0
+		for ; i < len(subtags) && len(subtags[i]) >= 2; i++ {
+			res = append(res, strings.ToLower(subtags[i]))
+			n++
+		}
+		if n == 0 {
+			return "", fmt.Errorf("%w: %q: empty extension %q", ErrInvalidLanguageTag, code, singleton)
+		}
+	}
+
+	// private use
+	if i < len(subtags) && strings.EqualFold(subtags[i], "x") {
+		if err := checkPrivateUse(subtags[i:]); err != nil {
+			return "", fmt.Errorf("%w: %q: %s", ErrInvalidLanguageTag, code, err)
+		}
+		for ; i < len(subtags); i++ {
+			res = append(res, strings.ToLower(subtags[i]))
+		}
+	}
+
+	if i < len(subtags) {
+		return "", fmt.Errorf("%w: %q: unexpected subtag %q", ErrInvalidLanguageTag, code, subtags[i])
+	}
+
+	return strings.Join(res, "-"), nil
+}
+
+// parentCode returns the code without the last subtag.
+// Extension and private use subtags are cut at once, starting from the
+// first singleton: parent of en-US-u-ca-gregory and en-US-x-a-b is en-US.
+// Returns false if the code has no parent, like x-whatever.
+func parentCode(code string) (string, bool) {
+	subtags := strings.Split(code, "-")
+	for i, s := range subtags {
+		if len(s) == 1 {
+			if i == 0 {
+				// private use only tag, like x-whatever.
+				return "", false
+			}
+			return strings.Join(subtags[:i], "-"), true
+		}
+	}
+
+	pos := strings.LastIndex(code, "-")
+	if pos == -1 {
+		return "", false
+	}
+	return code[:pos], true
+}
+
+// checkPrivateUse checks subtags like [x, private1, private2].
+func checkPrivateUse(subtags []string) error {
+	if len(subtags) < 2 {
+		return errors.New("empty private use")
+	}
+	return nil
+}
+
+func isVariant(s string) bool {
+	if len(s) >= 5 {
+		return true
+	}
+	return len(s) == 4 && s[0] >= '0' && s[0] <= '9'
+}
+
+func isAlpha(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i] | 0x20
+		if c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigit(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphaNum(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && ((c|0x20) < 'a' || (c|0x20) > 'z') {
+			return false
+		}
+	}
+	return true
+}
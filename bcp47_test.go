@@ -0,0 +1,136 @@
+package i18n
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCanonicalCode(t *testing.T) {
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"en", "en"},
+		{"EN", "en"},
+		{"en-us", "en-US"},
+		{"EN_us", "en-US"},
+		{"en-US", "en-US"},
+		{"zh-hans-cn", "zh-Hans-CN"},
+		{"ZH_HANT", "zh-Hant"},
+		{"es-419", "es-419"},
+		{"sr-latn-rs", "sr-Latn-RS"},
+		{"de-CH-1996", "de-CH-1996"},
+		{"sl-rozaj-BISKE", "sl-rozaj-biske"},
+		{"zh-yue-HK", "zh-yue-HK"},
+		{"en-US-u-CA-gregory", "en-US-u-ca-gregory"},
+		{"en-a-bbb-x-A-BB", "en-a-bbb-x-a-bb"},
+		{"X-Private", "x-private"},
+		{"und", "und"},
+	}
+
+	for _, tt := range tests {
+		got, err := CanonicalCode(tt.input)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestCanonicalCode_Invalid(t *testing.T) {
+
+	tests := []string{
+		"",
+		"?",
+		"e",
+		"en-",
+		"-en",
+		"en--US",
+		"en US",
+		"en-US-toolongsubtag",
+		"1en",
+		"en-u",
+		"en-u-ca-u-nu",
+		"de-1996-1996",
+		"en-x",
+		"en-US-a",
+		"en-ü",
+	}
+
+	for _, tt := range tests {
+		if got, err := CanonicalCode(tt); !errors.Is(err, ErrInvalidLanguageTag) {
+			t.Errorf("%q: expected ErrInvalidLanguageTag, got %q, %v", tt, got, err)
+		}
+	}
+}
+
+func TestParseStrict(t *testing.T) {
+	r := NewLanguageRegistry()
+
+	enUS, err := r.ParseStrict("EN_us")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, code := range []string{"en-US", "en-us", "en_US"} {
+		if got := r.Parse(code); got != enUS {
+			t.Errorf("%s: expected %d, got %d", code, enUS, got)
+		}
+		if got := r.Lookup(code); got != enUS {
+			t.Errorf("%s: expected %d, got %d", code, enUS, got)
+		}
+	}
+
+	if got := r.Code(r.NextLanguage(enUS)); got != "en" {
+		t.Errorf("expected 'en', got '%s'", got)
+	}
+
+	if _, err := r.ParseStrict("garbage!"); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+
+	if got := r.Parse("en--US"); got != Unknown {
+		t.Errorf("expected Unknown, got %d", got)
+	}
+}
+
+func TestParse_ExtensionParents(t *testing.T) {
+	r := NewLanguageRegistry()
+
+	li := r.Parse("en-US-u-ca-gregory")
+
+	var chain []string
+	for ; li != Unknown; li = r.NextLanguage(li) {
+		chain = append(chain, r.Code(li))
+	}
+
+	expected := []string{"en-US-u-ca-gregory", "en-US", "en"}
+	if !equalSlices(chain, expected) {
+		t.Errorf("expected %v, got %v", expected, chain)
+	}
+}
+
+func TestParentCode(t *testing.T) {
+	tests := []struct {
+		code   string
+		parent string
+		ok     bool
+	}{
+		{"zh-Hans-CN", "zh-Hans", true},
+		{"en", "", false},
+		{"en-US-u-ca-gregory", "en-US", true},
+		{"en-x-a-b", "en", true},
+		{"de-DE-t-en-x-priv", "de-DE", true},
+		{"x-a-b", "", false},
+	}
+	for _, tt := range tests {
+		parent, ok := parentCode(tt.code)
+		if parent != tt.parent || ok != tt.ok {
+			t.Errorf("%s: expected %q %v, got %q %v", tt.code, tt.parent, tt.ok, parent, ok)
+		}
+	}
+}
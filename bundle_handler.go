@@ -0,0 +1,313 @@
+package i18n
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// BundleEncoder compresses a marshalled translation bundle.
+type BundleEncoder func(data []byte) ([]byte, error)
+
+// GzipEncoder compresses data with gzip using the best compression.
+func GzipEncoder(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type bundleEncoding struct {
+	name string
+	enc  BundleEncoder
+}
+
+// bundleConfig defines options for BundleHandler.
+type bundleConfig struct {
+	// defaultNamespace is a name of the default namespace in the URL path.
+	defaultNamespace string
+
+	// encodings holds content codings in the order of preference.
+	encodings []bundleEncoding
+
+	cacheControl string
+}
+
+type BundleOption func(o *bundleConfig)
+
+// WithBundleDefaultNamespace assigns the name used in the URL path for
+// the default namespace. The default is "default": /en/default.json.
+func WithBundleDefaultNamespace(name string) BundleOption {
+	return func(o *bundleConfig) {
+		o.defaultNamespace = name
+	}
+}
+
+// WithBundleEncoding registers a precompressed content coding, like "br".
+// Encodings registered later are preferred. Registering an existing
+// name replaces the encoder, nil encoder removes it.
+func WithBundleEncoding(name string, enc BundleEncoder) BundleOption {
+	return func(o *bundleConfig) {
+		for i := range o.encodings {
+			if o.encodings[i].name == name {
+				o.encodings = append(o.encodings[:i], o.encodings[i+1:]...)
+				break
+			}
+		}
+		if enc != nil {
+			o.encodings = append([]bundleEncoding{{name: name, enc: enc}}, o.encodings...)
+		}
+	}
+}
+
+// WithBundleCacheControl assigns value of the Cache-Control response header.
+func WithBundleCacheControl(value string) BundleOption {
+	return func(o *bundleConfig) {
+		o.cacheControl = value
+	}
+}
+
+// errBundleNotFound is returned for a namespace without files.
+var errBundleNotFound = errors.New("bundle not found")
+
+type bundleKey struct {
+	lang      Language
+	namespace string
+}
+
+// bundle is a marshalled translation with precompressed variants.
+type bundle struct {
+	// generation holds the generation of the container snapshot.
+	generation uint64
+	etag       string
+	variants   map[string][]byte // content coding -> body, "" is identity
+}
+
+// BundleHandler serves translation bundles built by TranslationRequest.JSON
+// on paths /{lang}/{namespace}.json.
+//
+// Marshalled bundles are cached per language and namespace. Responses
+// carry ETag header and If-None-Match requests are answered with
+// 304 Not Modified. Unknown languages and namespaces without files are
+// answered with 404 Not Found.
+type BundleHandler struct {
+	tc  *TranslationContainer
+	cfg bundleConfig
+
+	mux   sync.RWMutex
+	cache map[bundleKey]*bundle
+}
+
+var _ http.Handler = (*BundleHandler)(nil)
+
+// BundleHandler returns a handler serving translation bundles.
+// Use http.StripPrefix if the handler is mounted to a subtree.
+func (tc *TranslationContainer) BundleHandler(opts ...BundleOption) *BundleHandler {
+	h := BundleHandler{
+		tc: tc,
+		cfg: bundleConfig{
+			defaultNamespace: "default",
+			encodings:        []bundleEncoding{{name: "gzip", enc: GzipEncoder}},
+		},
+		cache: make(map[bundleKey]*bundle),
+	}
+
+	for _, opt := range opts {
+		opt(&h.cfg)
+	}
+	return &h
+}
+
+// Invalidate drops all cached bundles.
+func (h *BundleHandler) Invalidate() {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	h.cache = make(map[bundleKey]*bundle)
+}
+
+// ServeHTTP implements http.Handler interface.
+func (h *BundleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	bk, ok := h.parsePath(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	b, err := h.bundle(bk)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	coding := h.negotiateEncoding(r.Header.Get("Accept-Encoding"))
+	etag := b.etag
+	if coding != "" {
+		etag = etag[:len(etag)-1] + "-" + coding + `"`
+	}
+
+	hdr := w.Header()
+	hdr.Set("ETag", etag)
+	if len(h.cfg.encodings) > 0 {
+		hdr.Add("Vary", "Accept-Encoding")
+	}
+	if h.cfg.cacheControl != "" {
+		hdr.Set("Cache-Control", h.cfg.cacheControl)
+	}
+
+	if etagMatch(r.Header.Get("If-None-Match"), b.etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	body := b.variants[coding]
+	hdr.Set("Content-Type", "application/json; charset=utf-8")
+	hdr.Set("Content-Length", strconv.Itoa(len(body)))
+	if coding != "" {
+		hdr.Set("Content-Encoding", coding)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(body)
+	}
+}
+
+// parsePath parses /{lang}/{namespace}.json.
+func (h *BundleHandler) parsePath(p string) (bundleKey, bool) {
+	lang, name, ok := strings.Cut(strings.TrimPrefix(p, "/"), "/")
+	if !ok || !strings.HasSuffix(name, ".json") {
+		return bundleKey{}, false
+	}
+
+	li := h.tc.Registry().Lookup(lang)
+	if li == Unknown {
+		return bundleKey{}, false
+	}
+
+	ns := strings.TrimSuffix(name, ".json")
+	if ns == "" || strings.Contains(ns, "/") {
+		return bundleKey{}, false
+	}
+	if ns == h.cfg.defaultNamespace {
+		ns = ""
+	}
+	return bundleKey{lang: li, namespace: ns}, true
+}
+
+// bundle returns cached bundle or builds it.
+// Bundles built before the container reload are rebuilt.
+// Namespaces without files are not found, so they are not cached.
+func (h *BundleHandler) bundle(bk bundleKey) (*bundle, error) {
+	snap := h.tc.snapshot()
+	if bk.namespace != "" && !snap.loadedNamespaces[bk.namespace] {
+		return nil, errBundleNotFound
+	}
+	gen := snap.generation
+
+	h.mux.RLock()
+	b, ok := h.cache[bk]
+	h.mux.RUnlock()
+	if ok && b.generation == gen {
+		return b, nil
+	}
+
+	data, err := h.tc.Namespace(bk.namespace, bk.lang).JSON()
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	b = &bundle{
+		generation: gen,
+		etag:       `"` + hex.EncodeToString(sum[:16]) + `"`,
+		variants:   map[string][]byte{"": data},
+	}
+	for _, e := range h.cfg.encodings {
+		buf, err := e.enc(data)
+		if err != nil {
+			return nil, err
+		}
+		b.variants[e.name] = buf
+	}
+
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	h.cache[bk] = b
+	return b, nil
+}
+
+// negotiateEncoding returns the preferred content coding accepted by
+// the client or "" for identity.
+func (h *BundleHandler) negotiateEncoding(header string) string {
+	if header == "" || len(h.cfg.encodings) == 0 {
+		return ""
+	}
+
+	accepted := make(map[string]bool)
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		name := strings.ToLower(strings.TrimSpace(params[0]))
+		q, ok := 1.0, true
+		for _, p := range params[1:] {
+			if k, v, found := strings.Cut(strings.TrimSpace(p), "="); found && strings.EqualFold(strings.TrimSpace(k), "q") {
+				q, ok = parseQuality(strings.TrimSpace(v))
+			}
+		}
+		accepted[name] = ok && q > 0
+	}
+
+	for _, e := range h.cfg.encodings {
+		if ok, found := accepted[e.name]; found {
+			if ok {
+				return e.name
+			}
+			continue
+		}
+		if accepted["*"] {
+			return e.name
+		}
+	}
+	return ""
+}
+
+// etagMatch implements weak comparison of If-None-Match value with etag.
+// Variants of the same bundle with different content codings match.
+func etagMatch(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+
+	opaque := strings.Trim(etag, `"`)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return true
+		}
+		tag = strings.Trim(strings.TrimPrefix(tag, "W/"), `"`)
+		if tag == opaque || strings.HasPrefix(tag, opaque+"-") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package i18n
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestBundleHandler(t *testing.T) {
+	r := NewLanguageRegistry()
+	tc := newTestContainer(t, r, WithPrimaryLanguage(r.Parse("en")))
+
+	brCalls := 0
+	h := tc.BundleHandler(
+		WithBundleCacheControl("max-age=60"),
+		WithBundleEncoding("br", func(data []byte) ([]byte, error) {
+			brCalls++
+			return append([]byte("br:"), data...), nil
+		}),
+	)
+
+	get := func(path string, hdr map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for k, v := range hdr {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/de/default.json", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	expected := `{"Cancel":{"v":"Abbrechen"},"Delete":{"v":"Löschen"},"Exit":{"v":"Sign out"},"Lift":{"v":"Aufzug"},"Save":{"v":"Speichern"}}`
+	if w.Body.String() != expected {
+		t.Errorf("expected %s, got %s", expected, w.Body.String())
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatalf("expected ETag")
+	}
+	if got := w.Header().Get("Cache-Control"); got != "max-age=60" {
+		t.Errorf("unexpected Cache-Control %s", got)
+	}
+
+	t.Run("NotModified", func(t *testing.T) {
+		w := get("/de/default.json", map[string]string{"If-None-Match": `"x", W/` + etag})
+		if w.Code != http.StatusNotModified {
+			t.Errorf("expected 304, got %d", w.Code)
+		}
+		if w.Body.Len() != 0 {
+			t.Errorf("expected empty body")
+		}
+	})
+
+	t.Run("Gzip", func(t *testing.T) {
+		w := get("/de/default.json", map[string]string{"Accept-Encoding": "gzip, br;q=0"})
+		if got := w.Header().Get("Content-Encoding"); got != "gzip" {
+			t.Fatalf("expected gzip, got %s", got)
+		}
+		if w.Header().Get("ETag") == etag {
+			t.Errorf("expected distinct ETag for gzip variant")
+		}
+		zr, err := gzip.NewReader(bytes.NewReader(w.Body.Bytes()))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		body, _ := io.ReadAll(zr)
+		if string(body) != expected {
+			t.Errorf("expected %s, got %s", expected, body)
+		}
+	})
+
+	t.Run("Brotli", func(t *testing.T) {
+		w := get("/de/default.json", map[string]string{"Accept-Encoding": "gzip, br"})
+		if got := w.Header().Get("Content-Encoding"); got != "br" {
+			t.Fatalf("expected br, got %s", got)
+		}
+		if brCalls != 1 {
+			t.Errorf("expected the bundle compressed once, got %d", brCalls)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		for _, p := range []string{"/fr/default.json", "/de/default", "/de.json", "/de/a/b.json", "/de/unknown.json"} {
+			if w := get(p, nil); w.Code != http.StatusNotFound {
+				t.Errorf("%s: expected 404, got %d", p, w.Code)
+			}
+		}
+	})
+
+	t.Run("NamespaceNotCached", func(t *testing.T) {
+		if w := get("/en-US/customer1.json", nil); w.Code != http.StatusOK {
+			t.Errorf("expected 200, got %d", w.Code)
+		}
+		for i := 0; i < 100; i++ {
+			get("/de/random"+strconv.Itoa(i)+".json", nil)
+		}
+
+		h.mux.RLock()
+		defer h.mux.RUnlock()
+		if len(h.cache) != 2 {
+			t.Errorf("expected 2 cached bundles, got %d", len(h.cache))
+		}
+	})
+
+	t.Run("MethodNotAllowed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/de/default.json", nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != http.StatusMethodNotAllowed {
+			t.Errorf("expected 405, got %d", w.Code)
+		}
+	})
+}
+
+func TestEtagMatch(t *testing.T) {
+	tests := []struct {
+		header string
+		match  bool
+	}{
+		{"", false},
+		{"*", true},
+		{`"abc"`, true},
+		{`W/"abc"`, true},
+		{`"abc-gzip"`, true},
+		{`"abcd"`, false},
+		{`"x", "abc"`, true},
+	}
+	for _, tt := range tests {
+		if got := etagMatch(tt.header, `"abc"`); got != tt.match {
+			t.Errorf("%q: expected %v, got %v", tt.header, tt.match, got)
+		}
+	}
+}
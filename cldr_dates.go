@@ -0,0 +1,575 @@
+package i18n
+
+import (
+	"strings"
+	"sync"
+)
+
+// dateData holds CLDR Gregorian calendar data of a locale. Empty fields
+// are inherited from the next locale in the fallback chain and finally
+// from English. Locales without data, like sw, use the data of their
+// parent and fallback languages, then English.
+type dateData struct {
+	// months holds format month names, standaloneMonths the names used
+	// without a day (LLL, LLLL), if they differ.
+	monthsAbbr           [12]string
+	monthsWide           [12]string
+	standaloneMonthsAbbr [12]string
+	standaloneMonthsWide [12]string
+
+	// days start with Sunday.
+	daysAbbr [7]string
+	daysWide [7]string
+
+	am, pm string
+
+	// dateFormats and timeFormats are indexed by Style.
+	dateFormats [4]string
+	timeFormats [4]string
+
+	// dateTimeFormat joins the time {0} and the date {1}.
+	dateTimeFormat string
+
+	// eras holds abbreviated names of BC and AD.
+	eras [2]string
+
+	// skeletons maps skeletons like "yMMMd" to patterns.
+	skeletons map[string]string
+
+	// relative maps RelativeUnit to future and past patterns by plural
+	// category, see parseCategoryPatterns.
+	relative map[RelativeUnit][2]string
+}
+
+var dateLocaleData = map[string]*dateData{
+	"en": {
+		monthsAbbr: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		monthsWide: [12]string{"January", "February", "March", "April", "May", "June", "July",
+			"August", "September", "October", "November", "December"},
+		daysAbbr:       [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+		daysWide:       [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		am:             "AM",
+		pm:             "PM",
+		dateFormats:    [4]string{"EEEE, MMMM d, y", "MMMM d, y", "MMM d, y", "M/d/yy"},
+		timeFormats:    [4]string{"h:mm:ss\u202fa zzzz", "h:mm:ss\u202fa z", "h:mm:ss\u202fa", "h:mm\u202fa"},
+		dateTimeFormat: "{1}, {0}",
+		eras:           [2]string{"BC", "AD"},
+		skeletons: map[string]string{
+			"y": "y", "yM": "M/y", "yMd": "M/d/y", "yMMM": "MMM y", "yMMMd": "MMM d, y",
+			"yMMMEd": "EEE, MMM d, y", "yMMMM": "MMMM y", "MMMd": "MMM d", "MMMEd": "EEE, MMM d",
+			"MMMMd": "MMMM d", "Md": "M/d", "Hm": "HH:mm", "Hms": "HH:mm:ss",
+			"hm": "h:mm\u202fa", "hms": "h:mm:ss\u202fa",
+		},
+		relative: map[RelativeUnit][2]string{
+			RelativeYear:   {"one: in {0} year; other: in {0} years", "one: {0} year ago; other: {0} years ago"},
+			RelativeMonth:  {"one: in {0} month; other: in {0} months", "one: {0} month ago; other: {0} months ago"},
+			RelativeWeek:   {"one: in {0} week; other: in {0} weeks", "one: {0} week ago; other: {0} weeks ago"},
+			RelativeDay:    {"one: in {0} day; other: in {0} days", "one: {0} day ago; other: {0} days ago"},
+			RelativeHour:   {"one: in {0} hour; other: in {0} hours", "one: {0} hour ago; other: {0} hours ago"},
+			RelativeMinute: {"one: in {0} minute; other: in {0} minutes", "one: {0} minute ago; other: {0} minutes ago"},
+			RelativeSecond: {"one: in {0} second; other: in {0} seconds", "one: {0} second ago; other: {0} seconds ago"},
+		},
+	},
+	"en-GB": {
+		am:          "am",
+		pm:          "pm",
+		dateFormats: [4]string{"EEEE d MMMM y", "d MMMM y", "d MMM y", "dd/MM/y"},
+		timeFormats: [4]string{"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"},
+		skeletons: map[string]string{
+			"yM": "MM/y", "yMd": "dd/MM/y", "yMMMd": "d MMM y", "yMMMEd": "EEE, d MMM y",
+			"MMMd": "d MMM", "MMMEd": "EEE d MMM", "MMMMd": "d MMMM", "Md": "dd/MM",
+		},
+	},
+	"de": {
+		monthsAbbr: [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+		monthsWide: [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli",
+			"August", "September", "Oktober", "November", "Dezember"},
+		daysAbbr:       [7]string{"So.", "Mo.", "Di.", "Mi.", "Do.", "Fr.", "Sa."},
+		daysWide:       [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		dateFormats:    [4]string{"EEEE, d. MMMM y", "d. MMMM y", "dd.MM.y", "dd.MM.yy"},
+		timeFormats:    [4]string{"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"},
+		dateTimeFormat: "{1}, {0}",
+		eras:           [2]string{"v. Chr.", "n. Chr."},
+		skeletons: map[string]string{
+			"yM": "M/y", "yMd": "d.M.y", "yMMM": "MMM y", "yMMMd": "d. MMM y", "yMMMEd": "E, d. MMM y",
+			"yMMMM": "MMMM y", "MMMd": "d. MMM", "MMMEd": "E, d. MMM", "MMMMd": "d. MMMM", "Md": "d.M.",
+		},
+		relative: map[RelativeUnit][2]string{
+			RelativeYear:   {"one: in {0} Jahr; other: in {0} Jahren", "one: vor {0} Jahr; other: vor {0} Jahren"},
+			RelativeMonth:  {"one: in {0} Monat; other: in {0} Monaten", "one: vor {0} Monat; other: vor {0} Monaten"},
+			RelativeWeek:   {"one: in {0} Woche; other: in {0} Wochen", "one: vor {0} Woche; other: vor {0} Wochen"},
+			RelativeDay:    {"one: in {0} Tag; other: in {0} Tagen", "one: vor {0} Tag; other: vor {0} Tagen"},
+			RelativeHour:   {"one: in {0} Stunde; other: in {0} Stunden", "one: vor {0} Stunde; other: vor {0} Stunden"},
+			RelativeMinute: {"one: in {0} Minute; other: in {0} Minuten", "one: vor {0} Minute; other: vor {0} Minuten"},
+			RelativeSecond: {"one: in {0} Sekunde; other: in {0} Sekunden", "one: vor {0} Sekunde; other: vor {0} Sekunden"},
+		},
+	},
+	"fr": {
+		monthsAbbr: [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		monthsWide: [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet",
+			"août", "septembre", "octobre", "novembre", "décembre"},
+		daysAbbr:       [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+		daysWide:       [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		dateFormats:    [4]string{"EEEE d MMMM y", "d MMMM y", "d MMM y", "dd/MM/y"},
+		timeFormats:    [4]string{"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"},
+		dateTimeFormat: "{1} {0}",
+		eras:           [2]string{"av. J.-C.", "ap. J.-C."},
+		skeletons: map[string]string{
+			"yM": "MM/y", "yMd": "dd/MM/y", "yMMM": "MMM y", "yMMMd": "d MMM y", "yMMMEd": "EEE d MMM y",
+			"yMMMM": "MMMM y", "MMMd": "d MMM", "MMMEd": "E d MMM", "MMMMd": "d MMMM", "Md": "dd/MM",
+		},
+		relative: map[RelativeUnit][2]string{
+			RelativeYear:   {"one: dans {0} an; other: dans {0} ans", "one: il y a {0} an; other: il y a {0} ans"},
+			RelativeMonth:  {"other: dans {0} mois", "other: il y a {0} mois"},
+			RelativeWeek:   {"one: dans {0} semaine; other: dans {0} semaines", "one: il y a {0} semaine; other: il y a {0} semaines"},
+			RelativeDay:    {"one: dans {0} jour; other: dans {0} jours", "one: il y a {0} jour; other: il y a {0} jours"},
+			RelativeHour:   {"one: dans {0} heure; other: dans {0} heures", "one: il y a {0} heure; other: il y a {0} heures"},
+			RelativeMinute: {"one: dans {0} minute; other: dans {0} minutes", "one: il y a {0} minute; other: il y a {0} minutes"},
+			RelativeSecond: {"one: dans {0} seconde; other: dans {0} secondes", "one: il y a {0} seconde; other: il y a {0} secondes"},
+		},
+	},
+	"es": {
+		monthsAbbr: [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		monthsWide: [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio",
+			"agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		daysAbbr:       [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+		daysWide:       [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		am:             "a.\u00a0m.",
+		pm:             "p.\u00a0m.",
+		dateFormats:    [4]string{"EEEE, d 'de' MMMM 'de' y", "d 'de' MMMM 'de' y", "d MMM y", "d/M/yy"},
+		timeFormats:    [4]string{"H:mm:ss (zzzz)", "H:mm:ss z", "H:mm:ss", "H:mm"},
+		dateTimeFormat: "{1}, {0}",
+		eras:           [2]string{"a. C.", "d. C."},
+		skeletons: map[string]string{
+			"yM": "M/y", "yMd": "d/M/y", "yMMM": "MMM y", "yMMMd": "d MMM y", "yMMMEd": "EEE, d MMM y",
+			"yMMMM": "MMMM 'de' y", "MMMd": "d MMM", "MMMEd": "E, d MMM", "MMMMd": "d 'de' MMMM", "Md": "d/M",
+			"Hm": "H:mm", "Hms": "H:mm:ss",
+		},
+		relative: map[RelativeUnit][2]string{
+			RelativeYear:   {"one: dentro de {0} año; other: dentro de {0} años", "one: hace {0} año; other: hace {0} años"},
+			RelativeMonth:  {"one: dentro de {0} mes; other: dentro de {0} meses", "one: hace {0} mes; other: hace {0} meses"},
+			RelativeWeek:   {"one: dentro de {0} semana; other: dentro de {0} semanas", "one: hace {0} semana; other: hace {0} semanas"},
+			RelativeDay:    {"one: dentro de {0} día; other: dentro de {0} días", "one: hace {0} día; other: hace {0} días"},
+			RelativeHour:   {"one: dentro de {0} hora; other: dentro de {0} horas", "one: hace {0} hora; other: hace {0} horas"},
+			RelativeMinute: {"one: dentro de {0} minuto; other: dentro de {0} minutos", "one: hace {0} minuto; other: hace {0} minutos"},
+			RelativeSecond: {"one: dentro de {0} segundo; other: dentro de {0} segundos", "one: hace {0} segundo; other: hace {0} segundos"},
+		},
+	},
+	"it": {
+		monthsAbbr: [12]string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+		monthsWide: [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio",
+			"agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		daysAbbr:       [7]string{"dom", "lun", "mar", "mer", "gio", "ven", "sab"},
+		daysWide:       [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+		dateFormats:    [4]string{"EEEE d MMMM y", "d MMMM y", "d MMM y", "dd/MM/yy"},
+		timeFormats:    [4]string{"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"},
+		dateTimeFormat: "{1}, {0}",
+		eras:           [2]string{"a.C.", "d.C."},
+		skeletons: map[string]string{
+			"yM": "M/y", "yMd": "d/M/y", "yMMM": "MMM y", "yMMMd": "d MMM y", "yMMMEd": "EEE d MMM y",
+			"yMMMM": "MMMM y", "MMMd": "d MMM", "MMMEd": "EEE d MMM", "MMMMd": "d MMMM", "Md": "d/M",
+		},
+		relative: map[RelativeUnit][2]string{
+			RelativeYear:   {"one: tra {0} anno; other: tra {0} anni", "one: {0} anno fa; other: {0} anni fa"},
+			RelativeMonth:  {"one: tra {0} mese; other: tra {0} mesi", "one: {0} mese fa; other: {0} mesi fa"},
+			RelativeWeek:   {"one: tra {0} settimana; other: tra {0} settimane", "one: {0} settimana fa; other: {0} settimane fa"},
+			RelativeDay:    {"one: tra {0} giorno; other: tra {0} giorni", "one: {0} giorno fa; other: {0} giorni fa"},
+			RelativeHour:   {"one: tra {0} ora; other: tra {0} ore", "one: {0} ora fa; other: {0} ore fa"},
+			RelativeMinute: {"one: tra {0} minuto; other: tra {0} minuti", "one: {0} minuto fa; other: {0} minuti fa"},
+			RelativeSecond: {"one: tra {0} secondo; other: tra {0} secondi", "one: {0} secondo fa; other: {0} secondi fa"},
+		},
+	},
+	"pt": {
+		monthsAbbr: [12]string{"jan.", "fev.", "mar.", "abr.", "mai.", "jun.", "jul.", "ago.", "set.", "out.", "nov.", "dez."},
+		monthsWide: [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho",
+			"agosto", "setembro", "outubro", "novembro", "dezembro"},
+		daysAbbr:       [7]string{"dom.", "seg.", "ter.", "qua.", "qui.", "sex.", "sáb."},
+		daysWide:       [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+		dateFormats:    [4]string{"EEEE, d 'de' MMMM 'de' y", "d 'de' MMMM 'de' y", "d 'de' MMM 'de' y", "dd/MM/y"},
+		timeFormats:    [4]string{"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"},
+		dateTimeFormat: "{1} {0}",
+		eras:           [2]string{"a.C.", "d.C."},
+		skeletons: map[string]string{
+			"yM": "MM/y", "yMd": "dd/MM/y", "yMMM": "MMM 'de' y", "yMMMd": "d 'de' MMM 'de' y",
+			"yMMMEd": "E, d 'de' MMM 'de' y", "yMMMM": "MMMM 'de' y", "MMMd": "d 'de' MMM",
+			"MMMEd": "E, d 'de' MMM", "MMMMd": "d 'de' MMMM", "Md": "d/M",
+		},
+		relative: map[RelativeUnit][2]string{
+			RelativeYear:   {"one: em {0} ano; other: em {0} anos", "one: há {0} ano; other: há {0} anos"},
+			RelativeMonth:  {"one: em {0} mês; other: em {0} meses", "one: há {0} mês; other: há {0} meses"},
+			RelativeWeek:   {"one: em {0} semana; other: em {0} semanas", "one: há {0} semana; other: há {0} semanas"},
+			RelativeDay:    {"one: em {0} dia; other: em {0} dias", "one: há {0} dia; other: há {0} dias"},
+			RelativeHour:   {"one: em {0} hora; other: em {0} horas", "one: há {0} hora; other: há {0} horas"},
+			RelativeMinute: {"one: em {0} minuto; other: em {0} minutos", "one: há {0} minuto; other: há {0} minutos"},
+			RelativeSecond: {"one: em {0} segundo; other: em {0} segundos", "one: há {0} segundo; other: há {0} segundos"},
+		},
+	},
+	"cs": {
+		monthsAbbr: [12]string{"led", "úno", "bře", "dub", "kvě", "čvn", "čvc", "srp", "zář", "říj", "lis", "pro"},
+		monthsWide: [12]string{"ledna", "února", "března", "dubna", "května", "června", "července",
+			"srpna", "září", "října", "listopadu", "prosince"},
+		standaloneMonthsWide: [12]string{"leden", "únor", "březen", "duben", "květen", "červen", "červenec",
+			"srpen", "září", "říjen", "listopad", "prosinec"},
+		daysAbbr:       [7]string{"ne", "po", "út", "st", "čt", "pá", "so"},
+		daysWide:       [7]string{"neděle", "pondělí", "úterý", "středa", "čtvrtek", "pátek", "sobota"},
+		am:             "dop.",
+		pm:             "odp.",
+		dateFormats:    [4]string{"EEEE d. MMMM y", "d. MMMM y", "d. M. y", "dd.MM.yy"},
+		timeFormats:    [4]string{"H:mm:ss zzzz", "H:mm:ss z", "H:mm:ss", "H:mm"},
+		dateTimeFormat: "{1} {0}",
+		eras:           [2]string{"př. n. l.", "n. l."},
+		skeletons: map[string]string{
+			"yM": "M/y", "yMd": "d. M. y", "yMMM": "LLLL y", "yMMMd": "d. M. y", "yMMMEd": "E d. M. y",
+			"yMMMM": "LLLL y", "MMMd": "d. M.", "MMMEd": "E d. M.", "MMMMd": "d. MMMM", "Md": "d. M.",
+			"Hm": "H:mm", "Hms": "H:mm:ss",
+		},
+		relative: map[RelativeUnit][2]string{
+			RelativeYear: {"one: za {0} rok; few: za {0} roky; many: za {0} roku; other: za {0} let",
+				"one: před {0} rokem; few: před {0} lety; many: před {0} roku; other: před {0} lety"},
+			RelativeMonth: {"one: za {0} měsíc; few: za {0} měsíce; many: za {0} měsíce; other: za {0} měsíců",
+				"one: před {0} měsícem; few: před {0} měsíci; many: před {0} měsíce; other: před {0} měsíci"},
+			RelativeWeek: {"one: za {0} týden; few: za {0} týdny; many: za {0} týdne; other: za {0} týdnů",
+				"one: před {0} týdnem; few: před {0} týdny; many: před {0} týdne; other: před {0} týdny"},
+			RelativeDay: {"one: za {0} den; few: za {0} dny; many: za {0} dne; other: za {0} dní",
+				"one: před {0} dnem; few: před {0} dny; many: před {0} dne; other: před {0} dny"},
+			RelativeHour: {"one: za {0} hodinu; few: za {0} hodiny; many: za {0} hodiny; other: za {0} hodin",
+				"one: před {0} hodinou; few: před {0} hodinami; many: před {0} hodiny; other: před {0} hodinami"},
+			RelativeMinute: {"one: za {0} minutu; few: za {0} minuty; many: za {0} minuty; other: za {0} minut",
+				"one: před {0} minutou; few: před {0} minutami; many: před {0} minuty; other: před {0} minutami"},
+			RelativeSecond: {"one: za {0} sekundu; few: za {0} sekundy; many: za {0} sekundy; other: za {0} sekund",
+				"one: před {0} sekundou; few: před {0} sekundami; many: před {0} sekundy; other: před {0} sekundami"},
+		},
+	},
+	"ru": {
+		monthsAbbr: [12]string{"янв.", "февр.", "мар.", "апр.", "мая", "июн.", "июл.", "авг.", "сент.", "окт.", "нояб.", "дек."},
+		monthsWide: [12]string{"января", "февраля", "марта", "апреля", "мая", "июня", "июля",
+			"августа", "сентября", "октября", "ноября", "декабря"},
+		standaloneMonthsAbbr: [12]string{"янв.", "февр.", "март", "апр.", "май", "июнь", "июль", "авг.", "сент.", "окт.", "нояб.", "дек."},
+		standaloneMonthsWide: [12]string{"январь", "февраль", "март", "апрель", "май", "июнь", "июль",
+			"август", "сентябрь", "октябрь", "ноябрь", "декабрь"},
+		daysAbbr:       [7]string{"вс", "пн", "вт", "ср", "чт", "пт", "сб"},
+		daysWide:       [7]string{"воскресенье", "понедельник", "вторник", "среда", "четверг", "пятница", "суббота"},
+		dateFormats:    [4]string{"EEEE, d MMMM y 'г'.", "d MMMM y 'г'.", "d MMM y 'г'.", "dd.MM.y"},
+		timeFormats:    [4]string{"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"},
+		dateTimeFormat: "{1}, {0}",
+		eras:           [2]string{"до н. э.", "н. э."},
+		skeletons: map[string]string{
+			"yM": "MM.y", "yMd": "dd.MM.y", "yMMM": "LLL y 'г'.", "yMMMd": "d MMM y 'г'.",
+			"yMMMEd": "EEE, d MMM y 'г'.", "yMMMM": "LLLL y 'г'.", "MMMd": "d MMM", "MMMEd": "ccc, d MMM",
+			"MMMMd": "d MMMM", "Md": "dd.MM",
+		},
+		relative: map[RelativeUnit][2]string{
+			RelativeYear: {"one: через {0} год; few: через {0} года; many: через {0} лет; other: через {0} года",
+				"one: {0} год назад; few: {0} года назад; many: {0} лет назад; other: {0} года назад"},
+			RelativeMonth: {"one: через {0} месяц; few: через {0} месяца; many: через {0} месяцев; other: через {0} месяца",
+				"one: {0} месяц назад; few: {0} месяца назад; many: {0} месяцев назад; other: {0} месяца назад"},
+			RelativeWeek: {"one: через {0} неделю; few: через {0} недели; many: через {0} недель; other: через {0} недели",
+				"one: {0} неделю назад; few: {0} недели назад; many: {0} недель назад; other: {0} недели назад"},
+			RelativeDay: {"one: через {0} день; few: через {0} дня; many: через {0} дней; other: через {0} дня",
+				"one: {0} день назад; few: {0} дня назад; many: {0} дней назад; other: {0} дня назад"},
+			RelativeHour: {"one: через {0} час; few: через {0} часа; many: через {0} часов; other: через {0} часа",
+				"one: {0} час назад; few: {0} часа назад; many: {0} часов назад; other: {0} часа назад"},
+			RelativeMinute: {"one: через {0} минуту; few: через {0} минуты; many: через {0} минут; other: через {0} минуты",
+				"one: {0} минуту назад; few: {0} минуты назад; many: {0} минут назад; other: {0} минуты назад"},
+			RelativeSecond: {"one: через {0} секунду; few: через {0} секунды; many: через {0} секунд; other: через {0} секунды",
+				"one: {0} секунду назад; few: {0} секунды назад; many: {0} секунд назад; other: {0} секунды назад"},
+		},
+	},
+	"pl": {
+		monthsAbbr: [12]string{"sty", "lut", "mar", "kwi", "maj", "cze", "lip", "sie", "wrz", "paź", "lis", "gru"},
+		monthsWide: [12]string{"stycznia", "lutego", "marca", "kwietnia", "maja", "czerwca", "lipca",
+			"sierpnia", "września", "października", "listopada", "grudnia"},
+		standaloneMonthsWide: [12]string{"styczeń", "luty", "marzec", "kwiecień", "maj", "czerwiec", "lipiec",
+			"sierpień", "wrzesień", "październik", "listopad", "grudzień"},
+		daysAbbr:       [7]string{"niedz.", "pon.", "wt.", "śr.", "czw.", "pt.", "sob."},
+		daysWide:       [7]string{"niedziela", "poniedziałek", "wtorek", "środa", "czwartek", "piątek", "sobota"},
+		dateFormats:    [4]string{"EEEE, d MMMM y", "d MMMM y", "d MMM y", "d.MM.y"},
+		timeFormats:    [4]string{"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"},
+		dateTimeFormat: "{1}, {0}",
+		eras:           [2]string{"p.n.e.", "n.e."},
+		skeletons: map[string]string{
+			"yM": "MM.y", "yMd": "d.MM.y", "yMMM": "LLL y", "yMMMd": "d MMM y", "yMMMEd": "EEE, d MMM y",
+			"yMMMM": "LLLL y", "MMMd": "d MMM", "MMMEd": "E, d MMM", "MMMMd": "d MMMM", "Md": "d.MM",
+		},
+		relative: map[RelativeUnit][2]string{
+			RelativeYear: {"one: za {0} rok; few: za {0} lata; many: za {0} lat; other: za {0} roku",
+				"one: {0} rok temu; few: {0} lata temu; many: {0} lat temu; other: {0} roku temu"},
+			RelativeMonth: {"one: za {0} miesiąc; few: za {0} miesiące; many: za {0} miesięcy; other: za {0} miesiąca",
+				"one: {0} miesiąc temu; few: {0} miesiące temu; many: {0} miesięcy temu; other: {0} miesiąca temu"},
+			RelativeWeek: {"one: za {0} tydzień; few: za {0} tygodnie; many: za {0} tygodni; other: za {0} tygodnia",
+				"one: {0} tydzień temu; few: {0} tygodnie temu; many: {0} tygodni temu; other: {0} tygodnia temu"},
+			RelativeDay: {"one: za {0} dzień; few: za {0} dni; many: za {0} dni; other: za {0} dnia",
+				"one: {0} dzień temu; few: {0} dni temu; many: {0} dni temu; other: {0} dnia temu"},
+			RelativeHour: {"one: za {0} godzinę; few: za {0} godziny; many: za {0} godzin; other: za {0} godziny",
+				"one: {0} godzinę temu; few: {0} godziny temu; many: {0} godzin temu; other: {0} godziny temu"},
+			RelativeMinute: {"one: za {0} minutę; few: za {0} minuty; many: za {0} minut; other: za {0} minuty",
+				"one: {0} minutę temu; few: {0} minuty temu; many: {0} minut temu; other: {0} minuty temu"},
+			RelativeSecond: {"one: za {0} sekundę; few: za {0} sekundy; many: za {0} sekund; other: za {0} sekundy",
+				"one: {0} sekundę temu; few: {0} sekundy temu; many: {0} sekund temu; other: {0} sekundy temu"},
+		},
+	},
+	"ja": {
+		monthsAbbr:     [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		monthsWide:     [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		daysAbbr:       [7]string{"日", "月", "火", "水", "木", "金", "土"},
+		daysWide:       [7]string{"日曜日", "月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日"},
+		am:             "午前",
+		pm:             "午後",
+		dateFormats:    [4]string{"y年M月d日EEEE", "y年M月d日", "y/MM/dd", "y/MM/dd"},
+		timeFormats:    [4]string{"H時mm分ss秒 zzzz", "H:mm:ss z", "H:mm:ss", "H:mm"},
+		dateTimeFormat: "{1} {0}",
+		eras:           [2]string{"紀元前", "西暦"},
+		skeletons: map[string]string{
+			"y": "y年", "yM": "y/M", "yMd": "y/M/d", "yMMM": "y年M月", "yMMMd": "y年M月d日",
+			"yMMMEd": "y年M月d日(E)", "yMMMM": "y年M月", "MMMd": "M月d日", "MMMEd": "M月d日(E)",
+			"MMMMd": "M月d日", "Md": "M/d", "Hm": "H:mm", "Hms": "H:mm:ss", "hm": "aK:mm", "hms": "aK:mm:ss",
+		},
+		relative: map[RelativeUnit][2]string{
+			RelativeYear:   {"other: {0} 年後", "other: {0} 年前"},
+			RelativeMonth:  {"other: {0} か月後", "other: {0} か月前"},
+			RelativeWeek:   {"other: {0} 週間後", "other: {0} 週間前"},
+			RelativeDay:    {"other: {0} 日後", "other: {0} 日前"},
+			RelativeHour:   {"other: {0} 時間後", "other: {0} 時間前"},
+			RelativeMinute: {"other: {0} 分後", "other: {0} 分前"},
+			RelativeSecond: {"other: {0} 秒後", "other: {0} 秒前"},
+		},
+	},
+	"hi": {
+		monthsAbbr: [12]string{"जन॰", "फ़र॰", "मार्च", "अप्रैल", "मई", "जून", "जुल॰", "अग॰", "सित॰", "अक्तू॰", "नव॰", "दिस॰"},
+		monthsWide: [12]string{"जनवरी", "फ़रवरी", "मार्च", "अप्रैल", "मई", "जून", "जुलाई",
+			"अगस्त", "सितंबर", "अक्तूबर", "नवंबर", "दिसंबर"},
+		daysAbbr:       [7]string{"रवि", "सोम", "मंगल", "बुध", "गुरु", "शुक्र", "शनि"},
+		daysWide:       [7]string{"रविवार", "सोमवार", "मंगलवार", "बुधवार", "गुरुवार", "शुक्रवार", "शनिवार"},
+		am:             "am",
+		pm:             "pm",
+		dateFormats:    [4]string{"EEEE, d MMMM y", "d MMMM y", "d MMM y", "d/M/yy"},
+		timeFormats:    [4]string{"h:mm:ss a zzzz", "h:mm:ss a z", "h:mm:ss a", "h:mm a"},
+		dateTimeFormat: "{1}, {0}",
+		eras:           [2]string{"ईसा-पूर्व", "ईस्वी"},
+		skeletons: map[string]string{
+			"yM": "M/y", "yMd": "d/M/y", "yMMM": "MMM y", "yMMMd": "d MMM y", "yMMMEd": "E, d MMM y",
+			"yMMMM": "MMMM y", "MMMd": "d MMM", "MMMEd": "E, d MMM", "MMMMd": "d MMMM", "Md": "d/M",
+			"hm": "h:mm a", "hms": "h:mm:ss a",
+		},
+		relative: map[RelativeUnit][2]string{
+			RelativeYear:   {"other: {0} वर्ष में", "other: {0} वर्ष पहले"},
+			RelativeMonth:  {"other: {0} माह में", "other: {0} माह पहले"},
+			RelativeWeek:   {"other: {0} सप्ताह में", "other: {0} सप्ताह पहले"},
+			RelativeDay:    {"other: {0} दिन में", "other: {0} दिन पहले"},
+			RelativeHour:   {"other: {0} घंटे में", "other: {0} घंटे पहले"},
+			RelativeMinute: {"other: {0} मिनट में", "other: {0} मिनट पहले"},
+			RelativeSecond: {"other: {0} सेकंड में", "other: {0} सेकंड पहले"},
+		},
+	},
+	"ar": {
+		monthsAbbr: [12]string{"يناير", "فبراير", "مارس", "أبريل", "مايو", "يونيو", "يوليو",
+			"أغسطس", "سبتمبر", "أكتوبر", "نوفمبر", "ديسمبر"},
+		monthsWide: [12]string{"يناير", "فبراير", "مارس", "أبريل", "مايو", "يونيو", "يوليو",
+			"أغسطس", "سبتمبر", "أكتوبر", "نوفمبر", "ديسمبر"},
+		daysAbbr:       [7]string{"الأحد", "الاثنين", "الثلاثاء", "الأربعاء", "الخميس", "الجمعة", "السبت"},
+		daysWide:       [7]string{"الأحد", "الاثنين", "الثلاثاء", "الأربعاء", "الخميس", "الجمعة", "السبت"},
+		am:             "ص",
+		pm:             "م",
+		dateFormats:    [4]string{"EEEE، d MMMM y", "d MMMM y", "dd\u200f/MM\u200f/y", "d\u200f/M\u200f/y"},
+		timeFormats:    [4]string{"h:mm:ss a zzzz", "h:mm:ss a z", "h:mm:ss a", "h:mm a"},
+		dateTimeFormat: "{1}، {0}",
+		eras:           [2]string{"ق.م", "م"},
+		skeletons: map[string]string{
+			"yM": "M\u200f/y", "yMd": "d\u200f/M\u200f/y", "yMMM": "MMM y", "yMMMd": "d MMM y", "yMMMEd": "E، d MMM y",
+			"yMMMM": "MMMM y", "MMMd": "d MMM", "MMMEd": "E، d MMM", "MMMMd": "d MMMM", "Md": "d/\u200fM",
+			"hm": "h:mm a", "hms": "h:mm:ss a",
+		},
+		relative: map[RelativeUnit][2]string{
+			RelativeYear: {"zero: خلال {0} سنة; one: خلال سنة واحدة; two: خلال سنتين; few: خلال {0} سنوات; many: خلال {0} سنة; other: خلال {0} سنة",
+				"zero: قبل {0} سنة; one: قبل سنة واحدة; two: قبل سنتين; few: قبل {0} سنوات; many: قبل {0} سنة; other: قبل {0} سنة"},
+			RelativeMonth: {"zero: خلال {0} شهر; one: خلال شهر واحد; two: خلال شهرين; few: خلال {0} أشهر; many: خلال {0} شهرًا; other: خلال {0} شهر",
+				"zero: قبل {0} شهر; one: قبل شهر واحد; two: قبل شهرين; few: قبل {0} أشهر; many: قبل {0} شهرًا; other: قبل {0} شهر"},
+			RelativeWeek: {"zero: خلال {0} أسبوع; one: خلال أسبوع واحد; two: خلال أسبوعين; few: خلال {0} أسابيع; many: خلال {0} أسبوعًا; other: خلال {0} أسبوع",
+				"zero: قبل {0} أسبوع; one: قبل أسبوع واحد; two: قبل أسبوعين; few: قبل {0} أسابيع; many: قبل {0} أسبوعًا; other: قبل {0} أسبوع"},
+			RelativeDay: {"zero: خلال {0} يوم; one: خلال يوم واحد; two: خلال يومين; few: خلال {0} أيام; many: خلال {0} يومًا; other: خلال {0} يوم",
+				"zero: قبل {0} يوم; one: قبل يوم واحد; two: قبل يومين; few: قبل {0} أيام; many: قبل {0} يومًا; other: قبل {0} يوم"},
+			RelativeHour: {"zero: خلال {0} ساعة; one: خلال ساعة واحدة; two: خلال ساعتين; few: خلال {0} ساعات; many: خلال {0} ساعة; other: خلال {0} ساعة",
+				"zero: قبل {0} ساعة; one: قبل ساعة واحدة; two: قبل ساعتين; few: قبل {0} ساعات; many: قبل {0} ساعة; other: قبل {0} ساعة"},
+			RelativeMinute: {"zero: خلال {0} دقيقة; one: خلال دقيقة واحدة; two: خلال دقيقتين; few: خلال {0} دقائق; many: خلال {0} دقيقة; other: خلال {0} دقيقة",
+				"zero: قبل {0} دقيقة; one: قبل دقيقة واحدة; two: قبل دقيقتين; few: قبل {0} دقائق; many: قبل {0} دقيقة; other: قبل {0} دقيقة"},
+			RelativeSecond: {"zero: خلال {0} ثانية; one: خلال ثانية واحدة; two: خلال ثانيتين; few: خلال {0} ثوانٍ; many: خلال {0} ثانية; other: خلال {0} ثانية",
+				"zero: قبل {0} ثانية; one: قبل ثانية واحدة; two: قبل ثانيتين; few: قبل {0} ثوانٍ; many: قبل {0} ثانية; other: قبل {0} ثانية"},
+		},
+	},
+	"sr": {
+		monthsAbbr: [12]string{"јан", "феб", "мар", "апр", "мај", "јун", "јул", "авг", "сеп", "окт", "нов", "дец"},
+		monthsWide: [12]string{"јануар", "фебруар", "март", "април", "мај", "јун", "јул",
+			"август", "септембар", "октобар", "новембар", "децембар"},
+		daysAbbr:       [7]string{"нед", "пон", "уто", "сре", "чет", "пет", "суб"},
+		daysWide:       [7]string{"недеља", "понедељак", "уторак", "среда", "четвртак", "петак", "субота"},
+		dateFormats:    [4]string{"EEEE, d. MMMM y.", "d. MMMM y.", "d. M. y.", "d.M.yy."},
+		timeFormats:    [4]string{"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"},
+		dateTimeFormat: "{1} {0}",
+		eras:           [2]string{"п. н. е.", "н. е."},
+		skeletons: map[string]string{
+			"yM": "M.y.", "yMd": "d.M.y.", "yMMM": "MMM y.", "yMMMd": "d. MMM y.", "yMMMEd": "E, d. MMM y.",
+			"yMMMM": "MMMM y.", "MMMd": "d. MMM", "MMMEd": "E d. MMM", "MMMMd": "d. MMMM", "Md": "d.M.",
+		},
+		relative: map[RelativeUnit][2]string{
+			RelativeYear: {"one: за {0} годину; few: за {0} године; other: за {0} година",
+				"one: пре {0} године; few: пре {0} године; other: пре {0} година"},
+			RelativeMonth: {"one: за {0} месец; few: за {0} месеца; other: за {0} месеци",
+				"one: пре {0} месеца; few: пре {0} месеца; other: пре {0} месеци"},
+			RelativeWeek: {"one: за {0} недељу; few: за {0} недеље; other: за {0} недеља",
+				"one: пре {0} недеље; few: пре {0} недеље; other: пре {0} недеља"},
+			RelativeDay: {"one: за {0} дан; few: за {0} дана; other: за {0} дана",
+				"one: пре {0} дана; few: пре {0} дана; other: пре {0} дана"},
+			RelativeHour: {"one: за {0} сат; few: за {0} сата; other: за {0} сати",
+				"one: пре {0} сата; few: пре {0} сата; other: пре {0} сати"},
+			RelativeMinute: {"one: за {0} минут; few: за {0} минута; other: за {0} минута",
+				"one: пре {0} минута; few: пре {0} минута; other: пре {0} минута"},
+			RelativeSecond: {"one: за {0} секунду; few: за {0} секунде; other: за {0} секунди",
+				"one: пре {0} секунде; few: пре {0} секунде; other: пре {0} секунди"},
+		},
+	},
+	// sr-Latn replaces all names of its parent sr, patterns are the same.
+	"sr-Latn": {
+		monthsAbbr: [12]string{"jan", "feb", "mar", "apr", "maj", "jun", "jul", "avg", "sep", "okt", "nov", "dec"},
+		monthsWide: [12]string{"januar", "februar", "mart", "april", "maj", "jun", "jul",
+			"avgust", "septembar", "oktobar", "novembar", "decembar"},
+		daysAbbr: [7]string{"ned", "pon", "uto", "sre", "čet", "pet", "sub"},
+		daysWide: [7]string{"nedelja", "ponedeljak", "utorak", "sreda", "četvrtak", "petak", "subota"},
+		eras:     [2]string{"p. n. e.", "n. e."},
+		relative: map[RelativeUnit][2]string{
+			RelativeYear: {"one: za {0} godinu; few: za {0} godine; other: za {0} godina",
+				"one: pre {0} godine; few: pre {0} godine; other: pre {0} godina"},
+			RelativeMonth: {"one: za {0} mesec; few: za {0} meseca; other: za {0} meseci",
+				"one: pre {0} meseca; few: pre {0} meseca; other: pre {0} meseci"},
+			RelativeWeek: {"one: za {0} nedelju; few: za {0} nedelje; other: za {0} nedelja",
+				"one: pre {0} nedelje; few: pre {0} nedelje; other: pre {0} nedelja"},
+			RelativeDay: {"one: za {0} dan; few: za {0} dana; other: za {0} dana",
+				"one: pre {0} dana; few: pre {0} dana; other: pre {0} dana"},
+			RelativeHour: {"one: za {0} sat; few: za {0} sata; other: za {0} sati",
+				"one: pre {0} sata; few: pre {0} sata; other: pre {0} sati"},
+			RelativeMinute: {"one: za {0} minut; few: za {0} minuta; other: za {0} minuta",
+				"one: pre {0} minuta; few: pre {0} minuta; other: pre {0} minuta"},
+			RelativeSecond: {"one: za {0} sekundu; few: za {0} sekunde; other: za {0} sekundi",
+				"one: pre {0} sekunde; few: pre {0} sekunde; other: pre {0} sekundi"},
+		},
+	},
+}
+
+// resolvedDates is dateData with all fields filled and relative
+// patterns parsed.
+type resolvedDates struct {
+	*dateData
+
+	// relative holds future and past patterns by plural category.
+	relative map[RelativeUnit][2]map[PluralCategory]string
+}
+
+// dateDataCache maps comma separated codes of the fallback chain to *resolvedDates.
+var dateDataCache sync.Map
+
+// resolveDateData returns date data following the language codes in
+// the order of the fallback chain. Parent codes of every code are tried
+// before the next code: de-AT, de, en-GB, en.
+func resolveDateData(codes []string) *resolvedDates {
+	cacheKey := strings.Join(codes, ",")
+	if v, ok := dateDataCache.Load(cacheKey); ok {
+		return v.(*resolvedDates)
+	}
+
+	var chain []*dateData
+	for _, c := range localeChain(codes) {
+		if d, found := dateLocaleData[c]; found {
+			chain = append(chain, d)
+		}
+	}
+
+	res := dateData{
+		skeletons: make(map[string]string),
+		relative:  make(map[RelativeUnit][2]string),
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		d := chain[i]
+		inheritStrings(res.monthsAbbr[:], d.monthsAbbr[:])
+		inheritStrings(res.monthsWide[:], d.monthsWide[:])
+		inheritStrings(res.daysAbbr[:], d.daysAbbr[:])
+		inheritStrings(res.daysWide[:], d.daysWide[:])
+		inheritStrings(res.dateFormats[:], d.dateFormats[:])
+		inheritStrings(res.timeFormats[:], d.timeFormats[:])
+		inheritStrings(res.eras[:], d.eras[:])
+		inheritString(&res.am, d.am)
+		inheritString(&res.pm, d.pm)
+		inheritString(&res.dateTimeFormat, d.dateTimeFormat)
+
+		// standalone names of a language replace inherited ones, or are
+		// the same as format names.
+		if d.monthsWide[0] != "" || d.standaloneMonthsWide[0] != "" {
+			res.standaloneMonthsAbbr = d.monthsAbbr
+			res.standaloneMonthsWide = d.monthsWide
+			inheritStrings(res.standaloneMonthsAbbr[:], d.standaloneMonthsAbbr[:])
+			inheritStrings(res.standaloneMonthsWide[:], d.standaloneMonthsWide[:])
+		}
+
+		for k, v := range d.skeletons {
+			res.skeletons[k] = v
+		}
+		for k, v := range d.relative {
+			res.relative[k] = v
+		}
+	}
+
+	rd := resolvedDates{
+		dateData: &res,
+		relative: make(map[RelativeUnit][2]map[PluralCategory]string, len(res.relative)),
+	}
+	for unit, p := range res.relative {
+		rd.relative[unit] = [2]map[PluralCategory]string{
+			parseCategoryPatterns(p[0]),
+			parseCategoryPatterns(p[1]),
+		}
+	}
+
+	v, _ := dateDataCache.LoadOrStore(cacheKey, &rd)
+	return v.(*resolvedDates)
+}
+
+// localeChain returns the codes followed by their parent codes, in the
+// order of the fallback chain, ending with "en": de-AT, de, en-GB, en.
+func localeChain(codes []string) []string {
+	var res []string
+	seen := make(map[string]bool)
+	for _, code := range append(codes[:len(codes):len(codes)], "en") {
+		for c, ok := code, true; ok; c, ok = parentCode(c) {
+			if !seen[c] {
+				res = append(res, c)
+				seen[c] = true
+			}
+		}
+	}
+	return res
+}
+
+func inheritStrings(dst, src []string) {
+	for i := range src {
+		inheritString(&dst[i], src[i])
+	}
+}
+
+// parseCategoryPatterns parses patterns by plural category like
+// "one: in {0} day; other: in {0} days". A pattern without category,
+// like "{0} km", is the "other" one.
+// Built-in data is expected to be valid, thus it panics on error.
+func parseCategoryPatterns(s string) map[PluralCategory]string {
+	res := make(map[PluralCategory]string)
+	if !strings.Contains(s, ":") {
+		res[PluralOther] = s
+		return res
+	}
+	for _, part := range strings.Split(s, ";") {
+		name, pattern, ok := strings.Cut(part, ":")
+		cat, found := ParsePluralCategory(strings.TrimSpace(name))
+		if !ok || !found {
+			panic("invalid plural patterns: " + s)
+		}
+		res[cat] = strings.TrimSpace(pattern)
+	}
+	return res
+}
@@ -0,0 +1,116 @@
+package i18n
+
+import (
+	"strings"
+	"sync"
+)
+
+// listPatterns holds CLDR list patterns: start, middle and end join
+// parts of lists of 3 and more items, two joins lists of 2 items.
+type listPatterns struct {
+	start, middle, end, two string
+}
+
+// listLocaleData maps locales to list patterns by ListStyle. Missing
+// styles are inherited from the next locale in the fallback chain and
+// finally from English.
+var listLocaleData = map[string]map[ListStyle]listPatterns{
+	"en": {
+		ListAnd:  {"{0}, {1}", "{0}, {1}", "{0}, and {1}", "{0} and {1}"},
+		ListOr:   {"{0}, {1}", "{0}, {1}", "{0}, or {1}", "{0} or {1}"},
+		ListUnit: {"{0}, {1}", "{0}, {1}", "{0}, {1}", "{0}, {1}"},
+	},
+	"en-GB": {
+		ListAnd: {"{0}, {1}", "{0}, {1}", "{0} and {1}", "{0} and {1}"},
+		ListOr:  {"{0}, {1}", "{0}, {1}", "{0} or {1}", "{0} or {1}"},
+	},
+	"en-IN": {
+		ListAnd: {"{0}, {1}", "{0}, {1}", "{0} and {1}", "{0} and {1}"},
+		ListOr:  {"{0}, {1}", "{0}, {1}", "{0} or {1}", "{0} or {1}"},
+	},
+	"de": {
+		ListAnd:  {"{0}, {1}", "{0}, {1}", "{0} und {1}", "{0} und {1}"},
+		ListOr:   {"{0}, {1}", "{0}, {1}", "{0} oder {1}", "{0} oder {1}"},
+		ListUnit: {"{0}, {1}", "{0}, {1}", "{0} und {1}", "{0}, {1}"},
+	},
+	"fr": {
+		ListAnd:  {"{0}, {1}", "{0}, {1}", "{0} et {1}", "{0} et {1}"},
+		ListOr:   {"{0}, {1}", "{0}, {1}", "{0} ou {1}", "{0} ou {1}"},
+		ListUnit: {"{0}, {1}", "{0}, {1}", "{0} et {1}", "{0} et {1}"},
+	},
+	"es": {
+		ListAnd:  {"{0}, {1}", "{0}, {1}", "{0} y {1}", "{0} y {1}"},
+		ListOr:   {"{0}, {1}", "{0}, {1}", "{0} o {1}", "{0} o {1}"},
+		ListUnit: {"{0}, {1}", "{0}, {1}", "{0} y {1}", "{0} y {1}"},
+	},
+	"it": {
+		ListAnd:  {"{0}, {1}", "{0}, {1}", "{0} e {1}", "{0} e {1}"},
+		ListOr:   {"{0}, {1}", "{0}, {1}", "{0} o {1}", "{0} o {1}"},
+		ListUnit: {"{0}, {1}", "{0}, {1}", "{0} e {1}", "{0} e {1}"},
+	},
+	"pt": {
+		ListAnd:  {"{0}, {1}", "{0}, {1}", "{0} e {1}", "{0} e {1}"},
+		ListOr:   {"{0}, {1}", "{0}, {1}", "{0} ou {1}", "{0} ou {1}"},
+		ListUnit: {"{0}, {1}", "{0}, {1}", "{0} e {1}", "{0} e {1}"},
+	},
+	"nl": {
+		ListAnd:  {"{0}, {1}", "{0}, {1}", "{0} en {1}", "{0} en {1}"},
+		ListOr:   {"{0}, {1}", "{0}, {1}", "{0} of {1}", "{0} of {1}"},
+		ListUnit: {"{0}, {1}", "{0}, {1}", "{0} en {1}", "{0} en {1}"},
+	},
+	"cs": {
+		ListAnd:  {"{0}, {1}", "{0}, {1}", "{0} a {1}", "{0} a {1}"},
+		ListOr:   {"{0}, {1}", "{0}, {1}", "{0} nebo {1}", "{0} nebo {1}"},
+		ListUnit: {"{0}, {1}", "{0}, {1}", "{0} a {1}", "{0} a {1}"},
+	},
+	"pl": {
+		ListAnd:  {"{0}, {1}", "{0}, {1}", "{0} i {1}", "{0} i {1}"},
+		ListOr:   {"{0}, {1}", "{0}, {1}", "{0} lub {1}", "{0} lub {1}"},
+		ListUnit: {"{0}, {1}", "{0}, {1}", "{0} i {1}", "{0} i {1}"},
+	},
+	"ru": {
+		ListAnd:  {"{0}, {1}", "{0}, {1}", "{0} и {1}", "{0} и {1}"},
+		ListOr:   {"{0}, {1}", "{0}, {1}", "{0} или {1}", "{0} или {1}"},
+		ListUnit: {"{0} {1}", "{0} {1}", "{0} {1}", "{0} {1}"},
+	},
+	"uk": {
+		ListAnd:  {"{0}, {1}", "{0}, {1}", "{0} і {1}", "{0} і {1}"},
+		ListOr:   {"{0}, {1}", "{0}, {1}", "{0} або {1}", "{0} або {1}"},
+		ListUnit: {"{0}, {1}", "{0}, {1}", "{0} і {1}", "{0} і {1}"},
+	},
+	"ja": {
+		ListAnd:  {"{0}、{1}", "{0}、{1}", "{0}、{1}", "{0}、{1}"},
+		ListOr:   {"{0}、{1}", "{0}、{1}", "{0}、または{1}", "{0}または{1}"},
+		ListUnit: {"{0} {1}", "{0} {1}", "{0} {1}", "{0} {1}"},
+	},
+	"zh": {
+		ListAnd:  {"{0}、{1}", "{0}、{1}", "{0}和{1}", "{0}和{1}"},
+		ListOr:   {"{0}、{1}", "{0}、{1}", "{0}或{1}", "{0}或{1}"},
+		ListUnit: {"{0}{1}", "{0}{1}", "{0}{1}", "{0}{1}"},
+	},
+}
+
+// listDataCache maps comma separated codes of the fallback chain to
+// resolved list patterns.
+var listDataCache sync.Map
+
+// resolveListData returns list patterns by ListStyle of the first
+// locale of codes and its fallback chain defining them.
+func resolveListData(codes []string) map[ListStyle]listPatterns {
+	cacheKey := strings.Join(codes, ",")
+	if v, ok := listDataCache.Load(cacheKey); ok {
+		return v.(map[ListStyle]listPatterns)
+	}
+
+	res := make(map[ListStyle]listPatterns)
+	for _, c := range localeChain(codes) {
+		for style, p := range listLocaleData[c] {
+			if _, ok := res[style]; !ok {
+				res[style] = p
+			}
+		}
+	}
+
+	v, _ := listDataCache.LoadOrStore(cacheKey, res)
+	return v.(map[ListStyle]listPatterns)
+}
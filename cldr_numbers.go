@@ -0,0 +1,326 @@
+package i18n
+
+import "sync"
+
+// numberData holds CLDR number symbols and patterns of a locale
+// (latn numbering system). Empty fields are inherited from the parent
+// locale and finally from root.
+type numberData struct {
+	decimal string
+	group   string
+	percent string
+	minus   string
+
+	decimalPattern  string
+	percentPattern  string
+	currencyPattern string
+
+	// minGrouping is the minimum number of digits in the highest group
+	// for grouping to be used: with 2, 1234 is not grouped.
+	minGrouping int
+
+	// compact holds short compact patterns in ascending order.
+	compact []compactPattern
+
+	// currencies holds currency symbols overriding the root symbols.
+	currencies map[string]string
+}
+
+// compactPattern is applied to numbers greater or equal to divisor.
+// "0" in the pattern is replaced by the number divided by divisor.
+type compactPattern struct {
+	divisor float64
+	pattern string
+}
+
+var rootNumberData = numberData{
+	decimal:         ".",
+	group:           ",",
+	percent:         "%",
+	minus:           "-",
+	decimalPattern:  "#,##0.###",
+	percentPattern:  "#,##0%",
+	currencyPattern: "¤\u00a0#,##0.00",
+	minGrouping:     1,
+	compact:         []compactPattern{{1e3, "0K"}, {1e6, "0M"}, {1e9, "0G"}, {1e12, "0T"}},
+}
+
+// numberLocaleData holds CLDR number data of locales differing from root.
+// Spaces are no-break (\u00a0) and narrow no-break (\u202f) as in CLDR.
+var numberLocaleData = map[string]numberData{
+	"en": {
+		currencyPattern: "¤#,##0.00",
+		compact:         []compactPattern{{1e3, "0K"}, {1e6, "0M"}, {1e9, "0B"}, {1e12, "0T"}},
+		currencies:      map[string]string{"USD": "$", "JPY": "¥"},
+	},
+	"en-AU": {
+		currencies: map[string]string{"AUD": "$", "USD": "USD"},
+	},
+	"en-CA": {
+		currencies: map[string]string{"CAD": "$", "USD": "US$"},
+	},
+	"en-GB": {
+		currencies: map[string]string{"USD": "US$"},
+	},
+	"en-IN": {
+		decimalPattern:  "#,##,##0.###",
+		percentPattern:  "#,##,##0%",
+		currencyPattern: "¤#,##,##0.00",
+		compact:         []compactPattern{{1e3, "0K"}, {1e5, "0L"}, {1e7, "0Cr"}},
+	},
+	"de": {
+		decimal:         ",",
+		group:           ".",
+		percentPattern:  "#,##0\u00a0%",
+		currencyPattern: "#,##0.00\u00a0¤",
+		compact:         []compactPattern{{1e6, "0\u00a0Mio."}, {1e9, "0\u00a0Mrd."}, {1e12, "0\u00a0Bio."}},
+	},
+	"de-AT": {
+		group:           "\u00a0",
+		currencyPattern: "¤\u00a0#,##0.00",
+	},
+	"de-CH": {
+		decimal:         ".",
+		group:           "’",
+		percentPattern:  "#,##0%",
+		currencyPattern: "¤\u00a0#,##0.00",
+	},
+	"fr": {
+		decimal:         ",",
+		group:           "\u202f",
+		percentPattern:  "#,##0\u202f%",
+		currencyPattern: "#,##0.00\u00a0¤",
+		compact:         []compactPattern{{1e3, "0\u00a0k"}, {1e6, "0\u00a0M"}, {1e9, "0\u00a0Md"}, {1e12, "0\u00a0Bn"}},
+	},
+	"fr-CA": {
+		group:      "\u00a0",
+		currencies: map[string]string{"CAD": "$", "USD": "$\u00a0US"},
+	},
+	"es": {
+		decimal:         ",",
+		group:           ".",
+		percentPattern:  "#,##0\u00a0%",
+		currencyPattern: "#,##0.00\u00a0¤",
+		minGrouping:     2,
+		compact:         []compactPattern{{1e3, "0\u00a0mil"}, {1e6, "0\u00a0M"}, {1e12, "0\u00a0B"}},
+	},
+	"es-419": {
+		decimal:         ".",
+		group:           ",",
+		currencyPattern: "¤#,##0.00",
+		minGrouping:     1,
+	},
+	"it": {
+		decimal:         ",",
+		group:           ".",
+		currencyPattern: "#,##0.00\u00a0¤",
+		compact:         []compactPattern{{1e6, "0\u00a0Mln"}, {1e9, "0\u00a0Mrd"}, {1e12, "0\u00a0Bln"}},
+	},
+	"pt": {
+		decimal:         ",",
+		group:           ".",
+		currencyPattern: "¤\u00a0#,##0.00",
+		compact:         []compactPattern{{1e3, "0\u00a0mil"}, {1e6, "0\u00a0mi"}, {1e9, "0\u00a0bi"}, {1e12, "0\u00a0tri"}},
+	},
+	"pt-PT": {
+		group:           "\u00a0",
+		currencyPattern: "#,##0.00\u00a0¤",
+		minGrouping:     2,
+		compact:         []compactPattern{{1e3, "0\u00a0mil"}, {1e6, "0\u00a0M"}, {1e9, "0\u00a0mM"}, {1e12, "0\u00a0Bi"}},
+	},
+	"nl": {
+		decimal:         ",",
+		group:           ".",
+		currencyPattern: "¤\u00a0#,##0.00",
+		compact:         []compactPattern{{1e3, "0K"}, {1e6, "0\u00a0mln."}, {1e9, "0\u00a0mld."}, {1e12, "0\u00a0bln."}},
+	},
+	"da": {
+		decimal:         ",",
+		group:           ".",
+		percentPattern:  "#,##0\u00a0%",
+		currencyPattern: "#,##0.00\u00a0¤",
+		compact:         []compactPattern{{1e3, "0\u00a0t"}, {1e6, "0\u00a0mio."}, {1e9, "0\u00a0mia."}, {1e12, "0\u00a0bio."}},
+		currencies:      map[string]string{"DKK": "kr."},
+	},
+	"sv": {
+		decimal:         ",",
+		group:           "\u00a0",
+		minus:           "\u2212",
+		percentPattern:  "#,##0\u00a0%",
+		currencyPattern: "#,##0.00\u00a0¤",
+		compact:         []compactPattern{{1e3, "0\u00a0tn"}, {1e6, "0\u00a0mn"}, {1e9, "0\u00a0md"}, {1e12, "0\u00a0bn"}},
+		currencies:      map[string]string{"SEK": "kr"},
+	},
+	"nb": {
+		decimal:         ",",
+		group:           "\u00a0",
+		minus:           "\u2212",
+		percentPattern:  "#,##0\u00a0%",
+		currencyPattern: "#,##0.00\u00a0¤",
+		compact:         []compactPattern{{1e3, "0k"}, {1e6, "0\u00a0mill."}, {1e9, "0\u00a0mrd."}, {1e12, "0\u00a0bill."}},
+		currencies:      map[string]string{"NOK": "kr"},
+	},
+	"fi": {
+		decimal:         ",",
+		group:           "\u00a0",
+		minus:           "\u2212",
+		percentPattern:  "#,##0\u00a0%",
+		currencyPattern: "#,##0.00\u00a0¤",
+		compact:         []compactPattern{{1e3, "0\u00a0t."}, {1e6, "0\u00a0milj."}, {1e9, "0\u00a0mrd."}, {1e12, "0\u00a0bilj."}},
+	},
+	"cs": {
+		decimal:         ",",
+		group:           "\u00a0",
+		percentPattern:  "#,##0\u00a0%",
+		currencyPattern: "#,##0.00\u00a0¤",
+		compact:         []compactPattern{{1e3, "0\u00a0tis."}, {1e6, "0\u00a0mil."}, {1e9, "0\u00a0mld."}, {1e12, "0\u00a0bil."}},
+		currencies:      map[string]string{"CZK": "Kč"},
+	},
+	"pl": {
+		decimal:         ",",
+		group:           "\u00a0",
+		currencyPattern: "#,##0.00\u00a0¤",
+		minGrouping:     2,
+		compact:         []compactPattern{{1e3, "0\u00a0tys."}, {1e6, "0\u00a0mln"}, {1e9, "0\u00a0mld"}, {1e12, "0\u00a0bln"}},
+		currencies:      map[string]string{"PLN": "zł"},
+	},
+	"ru": {
+		decimal:         ",",
+		group:           "\u00a0",
+		percentPattern:  "#,##0\u00a0%",
+		currencyPattern: "#,##0.00\u00a0¤",
+		compact:         []compactPattern{{1e3, "0\u00a0тыс."}, {1e6, "0\u00a0млн"}, {1e9, "0\u00a0млрд"}, {1e12, "0\u00a0трлн"}},
+		currencies:      map[string]string{"RUB": "₽"},
+	},
+	"uk": {
+		decimal:         ",",
+		group:           "\u00a0",
+		currencyPattern: "#,##0.00\u00a0¤",
+		compact:         []compactPattern{{1e3, "0\u00a0тис."}, {1e6, "0\u00a0млн"}, {1e9, "0\u00a0млрд"}, {1e12, "0\u00a0трлн"}},
+		currencies:      map[string]string{"UAH": "₴"},
+	},
+	"sr": {
+		decimal:         ",",
+		group:           ".",
+		currencyPattern: "#,##0.00\u00a0¤",
+		compact:         []compactPattern{{1e3, "0\u00a0хиљ."}, {1e6, "0\u00a0мил."}, {1e9, "0\u00a0млрд."}, {1e12, "0\u00a0бил."}},
+	},
+	"sr-Latn": {
+		compact: []compactPattern{{1e3, "0\u00a0hilj."}, {1e6, "0\u00a0mil."}, {1e9, "0\u00a0mlrd."}, {1e12, "0\u00a0bil."}},
+	},
+	"tr": {
+		decimal:         ",",
+		group:           ".",
+		percentPattern:  "%#,##0",
+		currencyPattern: "¤#,##0.00",
+		compact:         []compactPattern{{1e3, "0\u00a0B"}, {1e6, "0\u00a0Mn"}, {1e9, "0\u00a0Mr"}, {1e12, "0\u00a0Tn"}},
+		currencies:      map[string]string{"TRY": "₺"},
+	},
+	"ja": {
+		currencyPattern: "¤#,##0.00",
+		compact:         []compactPattern{{1e4, "0万"}, {1e8, "0億"}, {1e12, "0兆"}},
+		currencies:      map[string]string{"JPY": "￥", "CNY": "元"},
+	},
+	"zh": {
+		currencyPattern: "¤#,##0.00",
+		compact:         []compactPattern{{1e4, "0万"}, {1e8, "0亿"}, {1e12, "0万亿"}},
+		currencies:      map[string]string{"CNY": "¥"},
+	},
+	"ko": {
+		currencyPattern: "¤#,##0.00",
+		compact:         []compactPattern{{1e3, "0천"}, {1e4, "0만"}, {1e8, "0억"}, {1e12, "0조"}},
+	},
+	"hi": {
+		decimalPattern:  "#,##,##0.###",
+		percentPattern:  "#,##,##0%",
+		currencyPattern: "¤#,##,##0.00",
+		compact: []compactPattern{{1e3, "0\u00a0हज़ार"}, {1e5, "0\u00a0लाख"}, {1e7, "0\u00a0क॰"},
+			{1e9, "0\u00a0अ॰"}, {1e11, "0\u00a0ख॰"}},
+	},
+}
+
+// currencySymbols holds root currency symbols. Codes not listed are
+// used as symbols.
+var currencySymbols = map[string]string{
+	"AUD": "A$",
+	"BRL": "R$",
+	"CAD": "CA$",
+	"CNY": "CN¥",
+	"EUR": "€",
+	"GBP": "£",
+	"HKD": "HK$",
+	"ILS": "₪",
+	"INR": "₹",
+	"JPY": "JP¥",
+	"KRW": "₩",
+	"MXN": "MX$",
+	"NZD": "NZ$",
+	"TWD": "NT$",
+	"USD": "US$",
+	"VND": "₫",
+	"XAF": "FCFA",
+}
+
+// currencyDigits holds fraction digits of currencies not using 2 digits.
+var currencyDigits = map[string]int{
+	"BHD": 3,
+	"CLP": 0,
+	"IQD": 0,
+	"ISK": 0,
+	"JOD": 3,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"OMR": 3,
+	"TND": 3,
+	"VND": 0,
+}
+
+// numberDataCache maps language codes to resolved *numberData.
+var numberDataCache sync.Map
+
+// resolveNumberData returns number data of the canonical language code
+// with inherited fields filled from parent locales and root.
+func resolveNumberData(code string) *numberData {
+	if v, ok := numberDataCache.Load(code); ok {
+		return v.(*numberData)
+	}
+
+	chain := []numberData{}
+	for c, ok := code, true; ok; c, ok = parentCode(c) {
+		if d, found := numberLocaleData[c]; found {
+			chain = append(chain, d)
+		}
+	}
+
+	res := rootNumberData
+	res.currencies = make(map[string]string)
+	for i := len(chain) - 1; i >= 0; i-- {
+		d := &chain[i]
+		inheritString(&res.decimal, d.decimal)
+		inheritString(&res.group, d.group)
+		inheritString(&res.percent, d.percent)
+		inheritString(&res.minus, d.minus)
+		inheritString(&res.decimalPattern, d.decimalPattern)
+		inheritString(&res.percentPattern, d.percentPattern)
+		inheritString(&res.currencyPattern, d.currencyPattern)
+		if d.minGrouping != 0 {
+			res.minGrouping = d.minGrouping
+		}
+		if d.compact != nil {
+			res.compact = d.compact
+		}
+		for k, v := range d.currencies {
+			res.currencies[k] = v
+		}
+	}
+
+	v, _ := numberDataCache.LoadOrStore(code, &res)
+	return v.(*numberData)
+}
+
+func inheritString(dst *string, s string) {
+	if s != "" {
+		*dst = s
+	}
+}
@@ -0,0 +1,192 @@
+package i18n
+
+import (
+	"strings"
+	"sync"
+)
+
+// unitLocaleData maps locales to CLDR unit patterns, indexed by
+// UnitWidth: long, short and narrow. Patterns are given by plural
+// category, see parseCategoryPatterns. Missing units and widths are
+// inherited from the next locale in the fallback chain and finally
+// from English. An empty narrow pattern is the short one.
+var unitLocaleData = map[string]map[Unit][3]string{
+	"en": {
+		UnitKilometer:        {"one: {0} kilometer; other: {0} kilometers", "{0} km", "{0}km"},
+		UnitMeter:            {"one: {0} meter; other: {0} meters", "{0} m", "{0}m"},
+		UnitCentimeter:       {"one: {0} centimeter; other: {0} centimeters", "{0} cm", "{0}cm"},
+		UnitMile:             {"one: {0} mile; other: {0} miles", "{0} mi", "{0}mi"},
+		UnitKilogram:         {"one: {0} kilogram; other: {0} kilograms", "{0} kg", "{0}kg"},
+		UnitGram:             {"one: {0} gram; other: {0} grams", "{0} g", "{0}g"},
+		UnitLiter:            {"one: {0} liter; other: {0} liters", "{0} L", "{0}L"},
+		UnitDay:              {"one: {0} day; other: {0} days", "one: {0} day; other: {0} days", "{0}d"},
+		UnitHour:             {"one: {0} hour; other: {0} hours", "{0} hr", "{0}h"},
+		UnitMinute:           {"one: {0} minute; other: {0} minutes", "{0} min", "{0}m"},
+		UnitSecond:           {"one: {0} second; other: {0} seconds", "{0} sec", "{0}s"},
+		UnitMegabyte:         {"one: {0} megabyte; other: {0} megabytes", "{0} MB", "{0}MB"},
+		UnitGigabyte:         {"one: {0} gigabyte; other: {0} gigabytes", "{0} GB", "{0}GB"},
+		UnitCelsius:          {"one: {0} degree Celsius; other: {0} degrees Celsius", "{0}°C", "{0}°C"},
+		UnitKilometerPerHour: {"one: {0} kilometer per hour; other: {0} kilometers per hour", "{0} km/h", "{0}km/h"},
+	},
+	"en-GB": {
+		UnitKilometer:        {"one: {0} kilometre; other: {0} kilometres", "{0} km", "{0}km"},
+		UnitMeter:            {"one: {0} metre; other: {0} metres", "{0} m", "{0}m"},
+		UnitCentimeter:       {"one: {0} centimetre; other: {0} centimetres", "{0} cm", "{0}cm"},
+		UnitLiter:            {"one: {0} litre; other: {0} litres", "{0} l", "{0}l"},
+		UnitKilometerPerHour: {"one: {0} kilometre per hour; other: {0} kilometres per hour", "{0} km/h", "{0}km/h"},
+	},
+	"de": {
+		UnitKilometer:        {"{0} Kilometer", "{0} km", ""},
+		UnitMeter:            {"{0} Meter", "{0} m", ""},
+		UnitCentimeter:       {"{0} Zentimeter", "{0} cm", ""},
+		UnitMile:             {"one: {0} Meile; other: {0} Meilen", "{0} mi", ""},
+		UnitKilogram:         {"{0} Kilogramm", "{0} kg", ""},
+		UnitGram:             {"{0} Gramm", "{0} g", ""},
+		UnitLiter:            {"{0} Liter", "{0} l", ""},
+		UnitDay:              {"one: {0} Tag; other: {0} Tage", "{0} Tg.", "{0} T"},
+		UnitHour:             {"one: {0} Stunde; other: {0} Stunden", "{0} Std.", ""},
+		UnitMinute:           {"one: {0} Minute; other: {0} Minuten", "{0} Min.", ""},
+		UnitSecond:           {"one: {0} Sekunde; other: {0} Sekunden", "{0} Sek.", "{0} s"},
+		UnitMegabyte:         {"{0} Megabyte", "{0} MB", ""},
+		UnitGigabyte:         {"{0} Gigabyte", "{0} GB", ""},
+		UnitCelsius:          {"{0} Grad Celsius", "{0} °C", ""},
+		UnitKilometerPerHour: {"{0} Kilometer pro Stunde", "{0} km/h", ""},
+	},
+	"fr": {
+		UnitKilometer:        {"one: {0}\u00a0kilomètre; other: {0}\u00a0kilomètres", "{0}\u00a0km", "{0}km"},
+		UnitMeter:            {"one: {0}\u00a0mètre; other: {0}\u00a0mètres", "{0}\u00a0m", "{0}m"},
+		UnitCentimeter:       {"one: {0}\u00a0centimètre; other: {0}\u00a0centimètres", "{0}\u00a0cm", "{0}cm"},
+		UnitMile:             {"one: {0}\u00a0mille; other: {0}\u00a0milles", "{0}\u00a0mi", "{0}mi"},
+		UnitKilogram:         {"one: {0}\u00a0kilogramme; other: {0}\u00a0kilogrammes", "{0}\u00a0kg", "{0}kg"},
+		UnitGram:             {"one: {0}\u00a0gramme; other: {0}\u00a0grammes", "{0}\u00a0g", "{0}g"},
+		UnitLiter:            {"one: {0}\u00a0litre; other: {0}\u00a0litres", "{0}\u00a0l", "{0}l"},
+		UnitDay:              {"one: {0}\u00a0jour; other: {0}\u00a0jours", "{0}\u00a0j", "{0}j"},
+		UnitHour:             {"one: {0}\u00a0heure; other: {0}\u00a0heures", "{0}\u00a0h", "{0}h"},
+		UnitMinute:           {"one: {0}\u00a0minute; other: {0}\u00a0minutes", "{0}\u00a0min", "{0}min"},
+		UnitSecond:           {"one: {0}\u00a0seconde; other: {0}\u00a0secondes", "{0}\u00a0s", "{0}s"},
+		UnitMegabyte:         {"one: {0}\u00a0mégaoctet; other: {0}\u00a0mégaoctets", "{0}\u00a0Mo", "{0}Mo"},
+		UnitGigabyte:         {"one: {0}\u00a0gigaoctet; other: {0}\u00a0gigaoctets", "{0}\u00a0Go", "{0}Go"},
+		UnitCelsius:          {"one: {0}\u00a0degré Celsius; other: {0}\u00a0degrés Celsius", "{0}\u00a0°C", "{0}°C"},
+		UnitKilometerPerHour: {"one: {0}\u00a0kilomètre par heure; other: {0}\u00a0kilomètres par heure", "{0}\u00a0km/h", "{0}km/h"},
+	},
+	"cs": {
+		UnitKilometer:        {"one: {0} kilometr; few: {0} kilometry; many: {0} kilometru; other: {0} kilometrů", "{0} km", ""},
+		UnitMeter:            {"one: {0} metr; few: {0} metry; many: {0} metru; other: {0} metrů", "{0} m", ""},
+		UnitCentimeter:       {"one: {0} centimetr; few: {0} centimetry; many: {0} centimetru; other: {0} centimetrů", "{0} cm", ""},
+		UnitMile:             {"one: {0} míle; few: {0} míle; many: {0} míle; other: {0} mil", "{0} mi", ""},
+		UnitKilogram:         {"one: {0} kilogram; few: {0} kilogramy; many: {0} kilogramu; other: {0} kilogramů", "{0} kg", ""},
+		UnitGram:             {"one: {0} gram; few: {0} gramy; many: {0} gramu; other: {0} gramů", "{0} g", ""},
+		UnitLiter:            {"one: {0} litr; few: {0} litry; many: {0} litru; other: {0} litrů", "{0} l", ""},
+		UnitDay:              {"one: {0} den; few: {0} dny; many: {0} dne; other: {0} dní", "one: {0} den; few: {0} dny; many: {0} dne; other: {0} dní", "{0} d"},
+		UnitHour:             {"one: {0} hodina; few: {0} hodiny; many: {0} hodiny; other: {0} hodin", "{0} h", ""},
+		UnitMinute:           {"one: {0} minuta; few: {0} minuty; many: {0} minuty; other: {0} minut", "{0} min", ""},
+		UnitSecond:           {"one: {0} sekunda; few: {0} sekundy; many: {0} sekundy; other: {0} sekund", "{0} s", ""},
+		UnitMegabyte:         {"one: {0} megabajt; few: {0} megabajty; many: {0} megabajtu; other: {0} megabajtů", "{0} MB", ""},
+		UnitGigabyte:         {"one: {0} gigabajt; few: {0} gigabajty; many: {0} gigabajtu; other: {0} gigabajtů", "{0} GB", ""},
+		UnitCelsius:          {"one: {0} stupeň Celsia; few: {0} stupně Celsia; many: {0} stupně Celsia; other: {0} stupňů Celsia", "{0} °C", ""},
+		UnitKilometerPerHour: {"one: {0} kilometr za hodinu; few: {0} kilometry za hodinu; many: {0} kilometru za hodinu; other: {0} kilometrů za hodinu", "{0} km/h", ""},
+	},
+	"ru": {
+		UnitKilometer:        {"one: {0} километр; few: {0} километра; many: {0} километров; other: {0} километра", "{0} км", ""},
+		UnitMeter:            {"one: {0} метр; few: {0} метра; many: {0} метров; other: {0} метра", "{0} м", ""},
+		UnitCentimeter:       {"one: {0} сантиметр; few: {0} сантиметра; many: {0} сантиметров; other: {0} сантиметра", "{0} см", ""},
+		UnitMile:             {"one: {0} миля; few: {0} мили; many: {0} миль; other: {0} мили", "{0} ми", ""},
+		UnitKilogram:         {"one: {0} килограмм; few: {0} килограмма; many: {0} килограммов; other: {0} килограмма", "{0} кг", ""},
+		UnitGram:             {"one: {0} грамм; few: {0} грамма; many: {0} граммов; other: {0} грамма", "{0} г", ""},
+		UnitLiter:            {"one: {0} литр; few: {0} литра; many: {0} литров; other: {0} литра", "{0} л", ""},
+		UnitDay:              {"one: {0} день; few: {0} дня; many: {0} дней; other: {0} дня", "{0} дн.", ""},
+		UnitHour:             {"one: {0} час; few: {0} часа; many: {0} часов; other: {0} часа", "{0} ч", ""},
+		UnitMinute:           {"one: {0} минута; few: {0} минуты; many: {0} минут; other: {0} минуты", "{0} мин", ""},
+		UnitSecond:           {"one: {0} секунда; few: {0} секунды; many: {0} секунд; other: {0} секунды", "{0} с", ""},
+		UnitMegabyte:         {"one: {0} мегабайт; few: {0} мегабайта; many: {0} мегабайт; other: {0} мегабайта", "{0} МБ", ""},
+		UnitGigabyte:         {"one: {0} гигабайт; few: {0} гигабайта; many: {0} гигабайт; other: {0} гигабайта", "{0} ГБ", ""},
+		UnitCelsius:          {"one: {0} градус Цельсия; few: {0} градуса Цельсия; many: {0} градусов Цельсия; other: {0} градуса Цельсия", "{0} °C", ""},
+		UnitKilometerPerHour: {"one: {0} километр в час; few: {0} километра в час; many: {0} километров в час; other: {0} километра в час", "{0} км/ч", ""},
+	},
+	"es": {
+		UnitKilometer:        {"one: {0} kilómetro; other: {0} kilómetros", "{0} km", "{0}km"},
+		UnitMeter:            {"one: {0} metro; other: {0} metros", "{0} m", "{0}m"},
+		UnitCentimeter:       {"one: {0} centímetro; other: {0} centímetros", "{0} cm", "{0}cm"},
+		UnitMile:             {"one: {0} milla; other: {0} millas", "{0} mi", "{0}mi"},
+		UnitKilogram:         {"one: {0} kilogramo; other: {0} kilogramos", "{0} kg", "{0}kg"},
+		UnitGram:             {"one: {0} gramo; other: {0} gramos", "{0} g", "{0}g"},
+		UnitLiter:            {"one: {0} litro; other: {0} litros", "{0} l", "{0}l"},
+		UnitDay:              {"one: {0} día; other: {0} días", "{0} d", "{0}d"},
+		UnitHour:             {"one: {0} hora; other: {0} horas", "{0} h", "{0}h"},
+		UnitMinute:           {"one: {0} minuto; other: {0} minutos", "{0} min", "{0}min"},
+		UnitSecond:           {"one: {0} segundo; other: {0} segundos", "{0} s", "{0}s"},
+		UnitMegabyte:         {"one: {0} megabyte; other: {0} megabytes", "{0} MB", "{0}MB"},
+		UnitGigabyte:         {"one: {0} gigabyte; other: {0} gigabytes", "{0} GB", "{0}GB"},
+		UnitCelsius:          {"one: {0} grado Celsius; other: {0} grados Celsius", "{0} °C", "{0}°C"},
+		UnitKilometerPerHour: {"one: {0} kilómetro por hora; other: {0} kilómetros por hora", "{0} km/h", "{0}km/h"},
+	},
+	"pt": {
+		UnitKilometer:        {"one: {0} quilômetro; other: {0} quilômetros", "{0} km", "{0}km"},
+		UnitMeter:            {"one: {0} metro; other: {0} metros", "{0} m", "{0}m"},
+		UnitCentimeter:       {"one: {0} centímetro; other: {0} centímetros", "{0} cm", "{0}cm"},
+		UnitMile:             {"one: {0} milha; other: {0} milhas", "{0} mi", "{0}mi"},
+		UnitKilogram:         {"one: {0} quilograma; other: {0} quilogramas", "{0} kg", "{0}kg"},
+		UnitGram:             {"one: {0} grama; other: {0} gramas", "{0} g", "{0}g"},
+		UnitLiter:            {"one: {0} litro; other: {0} litros", "{0} l", "{0}l"},
+		UnitDay:              {"one: {0} dia; other: {0} dias", "one: {0} dia; other: {0} dias", "{0}d"},
+		UnitHour:             {"one: {0} hora; other: {0} horas", "{0} h", "{0}h"},
+		UnitMinute:           {"one: {0} minuto; other: {0} minutos", "{0} min", "{0}min"},
+		UnitSecond:           {"one: {0} segundo; other: {0} segundos", "{0} s", "{0}s"},
+		UnitMegabyte:         {"one: {0} megabyte; other: {0} megabytes", "{0} MB", "{0}MB"},
+		UnitGigabyte:         {"one: {0} gigabyte; other: {0} gigabytes", "{0} GB", "{0}GB"},
+		UnitCelsius:          {"one: {0} grau Celsius; other: {0} graus Celsius", "{0} °C", "{0}°C"},
+		UnitKilometerPerHour: {"one: {0} quilômetro por hora; other: {0} quilômetros por hora", "{0} km/h", "{0}km/h"},
+	},
+	"ja": {
+		UnitKilometer:        {"{0} キロメートル", "{0} km", "{0}km"},
+		UnitMeter:            {"{0} メートル", "{0} m", "{0}m"},
+		UnitCentimeter:       {"{0} センチメートル", "{0} cm", "{0}cm"},
+		UnitMile:             {"{0} マイル", "{0} マイル", "{0}マイル"},
+		UnitKilogram:         {"{0} キログラム", "{0} kg", "{0}kg"},
+		UnitGram:             {"{0} グラム", "{0} g", "{0}g"},
+		UnitLiter:            {"{0} リットル", "{0} L", "{0}L"},
+		UnitDay:              {"{0} 日", "{0} 日", "{0}日"},
+		UnitHour:             {"{0} 時間", "{0} 時間", "{0}時間"},
+		UnitMinute:           {"{0} 分", "{0} 分", "{0}分"},
+		UnitSecond:           {"{0} 秒", "{0} 秒", "{0}秒"},
+		UnitMegabyte:         {"{0} メガバイト", "{0} MB", "{0}MB"},
+		UnitGigabyte:         {"{0} ギガバイト", "{0} GB", "{0}GB"},
+		UnitCelsius:          {"セ氏 {0} 度", "{0}°C", "{0}°C"},
+		UnitKilometerPerHour: {"時速 {0} キロメートル", "{0} km/h", "{0}km/h"},
+	},
+}
+
+// unitDataCache maps comma separated codes of the fallback chain to
+// resolved unit patterns.
+var unitDataCache sync.Map
+
+// resolvedUnits maps units to patterns by plural category, indexed by
+// UnitWidth.
+type resolvedUnits map[Unit][3]map[PluralCategory]string
+
+// resolveUnitData returns unit patterns of the first locale of codes
+// and its fallback chain defining them.
+func resolveUnitData(codes []string) resolvedUnits {
+	cacheKey := strings.Join(codes, ",")
+	if v, ok := unitDataCache.Load(cacheKey); ok {
+		return v.(resolvedUnits)
+	}
+
+	res := make(resolvedUnits)
+	for _, c := range localeChain(codes) {
+		for unit, widths := range unitLocaleData[c] {
+			p := res[unit]
+			for w, s := range widths {
+				if s == "" && w == int(UnitNarrow) {
+					s = widths[UnitShort]
+				}
+				if p[w] == nil && s != "" {
+					p[w] = parseCategoryPatterns(s)
+				}
+			}
+			res[unit] = p
+		}
+	}
+	v, _ := unitDataCache.LoadOrStore(cacheKey, res)
+	return v.(resolvedUnits)
+}
@@ -0,0 +1,322 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrUnsupportedSkeleton is returned if there is no pattern for a date
+// skeleton.
+var ErrUnsupportedSkeleton = errors.New("unsupported skeleton")
+
+// Style is a length of CLDR date and time formats.
+type Style int8
+
+const (
+	StyleFull Style = iota
+	StyleLong
+	StyleMedium
+	StyleShort
+)
+
+// RelativeUnit is a unit of relative time.
+type RelativeUnit int8
+
+const (
+	RelativeSecond RelativeUnit = iota
+	RelativeMinute
+	RelativeHour
+	RelativeDay
+	RelativeWeek
+	RelativeMonth
+	RelativeYear
+)
+
+// DateFormat formats dates, times and relative times by CLDR data of
+// a language.
+type DateFormat struct {
+	code    string
+	d       *resolvedDates
+	numbers NumberFormat
+}
+
+// Dates returns the date format of the language li of the default registry.
+func Dates(li Language) DateFormat {
+	return defaultRegistry.Dates(li)
+}
+
+// Dates returns the date format of the language li. CLDR data is taken
+// following the fallback chain of li, like translations: if de-AT falls
+// back to de and en, data of de-AT, de and en is used in this order.
+//
+// Built-in data covers ar, cs, de, en, en-GB, es, fr, hi, it, ja, pl,
+// pt, ru, sr and sr-Latn. Other locales use the data of their parent
+// and fallback languages, and English if none of them is covered.
+// Numbers in dates are formatted by Numbers of li, which has no ar data
+// and uses root symbols for it.
+func (r *LanguageRegistry) Dates(li Language) DateFormat {
+	code := r.Code(li)
+	return DateFormat{
+		code:    code,
+		d:       resolveDateData(r.chainCodes(li)),
+		numbers: newNumberFormat(code),
+	}
+}
+
+// Dates returns the date format of the requested language.
+func (tr TranslationRequest) Dates() DateFormat {
+	if tr.tc == nil {
+		return defaultRegistry.Dates(tr.lang)
+	}
+	return tr.tc.Registry().Dates(tr.lang)
+}
+
+// Date formats the date of t: "October 17, 2026" in English with
+// StyleLong, "17. října 2026" in Czech.
+func (df DateFormat) Date(t time.Time, style Style) string {
+	return df.Pattern(t, df.d.dateFormats[styleIndex(style)])
+}
+
+// Time formats the time of t: "3:04 PM" in English with StyleShort.
+func (df DateFormat) Time(t time.Time, style Style) string {
+	return df.Pattern(t, df.d.timeFormats[styleIndex(style)])
+}
+
+// DateTime formats the date and the time of t.
+func (df DateFormat) DateTime(t time.Time, dateStyle, timeStyle Style) string {
+	return strings.NewReplacer(
+		"{0}", df.Time(t, timeStyle),
+		"{1}", df.Date(t, dateStyle),
+	).Replace(df.d.dateTimeFormat)
+}
+
+// Skeleton formats t by the CLDR skeleton, which lists fields without
+// order and punctuation: "yMMMd" is "Oct 17, 2026" in English and
+// "17. Okt. 2026" in German.
+//
+// Supported skeletons are y, yM, yMd, yMMM, yMMMd, yMMMEd, yMMMM, MMMd,
+// MMMEd, MMMMd, Md, Hm, Hms, hm and hms.
+func (df DateFormat) Skeleton(t time.Time, skeleton string) (string, error) {
+	pattern, ok := df.d.skeletons[skeleton]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedSkeleton, skeleton)
+	}
+	return df.Pattern(t, pattern), nil
+}
+
+func styleIndex(style Style) int {
+	if style < StyleFull || style > StyleShort {
+		return int(StyleMedium)
+	}
+	return int(style)
+}
+
+// Pattern formats t by the CLDR (LDML) pattern like "d. MMMM y".
+// Text in apostrophes is literal, two apostrophes are an apostrophe.
+func (df DateFormat) Pattern(t time.Time, pattern string) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+
+		if c == '\'' {
+			if i+1 < len(pattern) && pattern[i+1] == '\'' {
+				sb.WriteByte('\'')
+				i += 2
+				continue
+			}
+			// quoted text, two apostrophes in it are an apostrophe
+			for i++; i < len(pattern); i++ {
+				if pattern[i] != '\'' {
+					sb.WriteByte(pattern[i])
+					continue
+				}
+				if i+1 < len(pattern) && pattern[i+1] == '\'' {
+					sb.WriteByte('\'')
+					i++
+					continue
+				}
+				i++
+				break
+			}
+			continue
+		}
+
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+			sb.WriteByte(c)
+			i++
+			continue
+		}
+
+		n := 1
+		for i+n < len(pattern) && pattern[i+n] == c {
+			n++
+		}
+		df.field(&sb, t, c, n)
+		i += n
+	}
+	return sb.String()
+}
+
+// field writes the pattern field c repeated n times.
+func (df DateFormat) field(sb *strings.Builder, t time.Time, c byte, n int) {
+	switch c {
+	case 'G':
+		if t.Year() > 0 {
+			sb.WriteString(df.d.eras[1])
+		} else {
+			sb.WriteString(df.d.eras[0])
+		}
+	case 'y':
+		y := t.Year()
+		if n == 2 {
+			y %= 100
+		}
+		writePadded(sb, y, n)
+	case 'M', 'L':
+		m := int(t.Month()) - 1
+		abbr, wide := df.d.monthsAbbr, df.d.monthsWide
+		if c == 'L' {
+			abbr, wide = df.d.standaloneMonthsAbbr, df.d.standaloneMonthsWide
+		}
+		switch {
+		case n <= 2:
+			writePadded(sb, m+1, n)
+		case n == 3:
+			sb.WriteString(abbr[m])
+		case n == 4:
+			sb.WriteString(wide[m])
+		default:
+			writeNarrow(sb, wide[m])
+		}
+	case 'd':
+		writePadded(sb, t.Day(), n)
+	case 'D':
+		writePadded(sb, t.YearDay(), n)
+	case 'E', 'c', 'e':
+		wd := int(t.Weekday())
+		switch {
+		case n <= 3:
+			sb.WriteString(df.d.daysAbbr[wd])
+		case n == 4:
+			sb.WriteString(df.d.daysWide[wd])
+		default:
+			writeNarrow(sb, df.d.daysWide[wd])
+		}
+	case 'a':
+		if t.Hour() < 12 {
+			sb.WriteString(df.d.am)
+		} else {
+			sb.WriteString(df.d.pm)
+		}
+	case 'h':
+		h := t.Hour() % 12
+		if h == 0 {
+			h = 12
+		}
+		writePadded(sb, h, n)
+	case 'H':
+		writePadded(sb, t.Hour(), n)
+	case 'K':
+		writePadded(sb, t.Hour()%12, n)
+	case 'k':
+		h := t.Hour()
+		if h == 0 {
+			h = 24
+		}
+		writePadded(sb, h, n)
+	case 'm':
+		writePadded(sb, t.Minute(), n)
+	case 's':
+		writePadded(sb, t.Second(), n)
+	case 'S':
+		frac := fmt.Sprintf("%09d", t.Nanosecond())
+		if n > len(frac) {
+			n = len(frac)
+		}
+		sb.WriteString(frac[:n])
+	case 'z', 'v', 'V':
+		sb.WriteString(t.Format("MST"))
+	case 'Z', 'x':
+		sb.WriteString(t.Format("-0700"))
+	case 'X', 'O':
+		sb.WriteString(t.Format("Z07:00"))
+	default:
+		sb.WriteString(strings.Repeat(string(c), n))
+	}
+}
+
+func writePadded(sb *strings.Builder, v, width int) {
+	s := strconv.Itoa(v)
+	if len(s) < width {
+		sb.WriteString(strings.Repeat("0", width-len(s)))
+	}
+	sb.WriteString(s)
+}
+
+// writeNarrow writes the first letter of the name.
+func writeNarrow(sb *strings.Builder, name string) {
+	_, size := utf8.DecodeRuneInString(name)
+	sb.WriteString(strings.ToUpper(name[:size]))
+}
+
+// Relative formats n units relative to now: "in 3 hours" for 3 and
+// "3 hours ago" for -3 in English, "před 3 hodinami" in Czech.
+func (df DateFormat) Relative(n int, unit RelativeUnit) string {
+	patterns, ok := df.d.relative[unit]
+	if !ok {
+		patterns = df.d.relative[RelativeSecond]
+	}
+
+	p := patterns[0]
+	if n < 0 {
+		p = patterns[1]
+		n = -n
+	}
+
+	pattern, ok := p[pluralCategory(cardinalRuleSet(), df.code, n)]
+	if !ok {
+		pattern = p[PluralOther]
+	}
+	return strings.ReplaceAll(pattern, "{0}", df.numbers.Decimal(n))
+}
+
+// RelativeTime formats the duration relative to now in the largest
+// fitting unit: -3h20m is "3 hours ago", 48h is "in 2 days". Months are
+// counted as 30 days, years as 365 days.
+func (df DateFormat) RelativeTime(d time.Duration) string {
+	const (
+		day   = 24 * time.Hour
+		week  = 7 * day
+		month = 30 * day
+		year  = 365 * day
+	)
+
+	abs := d
+	if abs < 0 {
+		abs = -abs
+	}
+
+	units := []struct {
+		size time.Duration
+		unit RelativeUnit
+	}{
+		{year, RelativeYear},
+		{month, RelativeMonth},
+		{week, RelativeWeek},
+		{day, RelativeDay},
+		{time.Hour, RelativeHour},
+		{time.Minute, RelativeMinute},
+	}
+	for _, u := range units {
+		if abs >= u.size {
+			return df.Relative(int(math.Trunc(float64(d)/float64(u.size))), u.unit)
+		}
+	}
+	return df.Relative(int(d/time.Second), RelativeSecond)
+}
@@ -0,0 +1,154 @@
+package i18n
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDateFormat(t *testing.T) {
+	r := NewLanguageRegistry()
+	tm := time.Date(2026, 10, 17, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		code     string
+		format   func(df DateFormat) string
+		expected string
+	}{
+		{"en", func(df DateFormat) string { return df.Date(tm, StyleFull) }, "Saturday, October 17, 2026"},
+		{"en", func(df DateFormat) string { return df.Date(tm, StyleMedium) }, "Oct 17, 2026"},
+		{"en", func(df DateFormat) string { return df.Date(tm, StyleShort) }, "10/17/26"},
+		{"en", func(df DateFormat) string { return df.Time(tm, StyleShort) }, "3:04 PM"},
+		{"en", func(df DateFormat) string { return df.DateTime(tm, StyleMedium, StyleShort) }, "Oct 17, 2026, 3:04 PM"},
+		{"en-GB", func(df DateFormat) string { return df.Date(tm, StyleShort) }, "17/10/2026"},
+		{"en-GB", func(df DateFormat) string { return df.Date(tm, StyleLong) }, "17 October 2026"},
+		{"cs", func(df DateFormat) string { return df.Date(tm, StyleLong) }, "17. října 2026"},
+		{"cs", func(df DateFormat) string { return df.Date(tm, StyleFull) }, "sobota 17. října 2026"},
+		{"de", func(df DateFormat) string { return df.Date(tm, StyleMedium) }, "17.10.2026"},
+		{"de-AT", func(df DateFormat) string { return df.DateTime(tm, StyleLong, StyleShort) }, "17. Oktober 2026, 15:04"},
+		{"es", func(df DateFormat) string { return df.Date(tm, StyleLong) }, "17 de octubre de 2026"},
+		{"ru", func(df DateFormat) string { return df.Date(tm, StyleLong) }, "17 октября 2026 г."},
+		{"ja", func(df DateFormat) string { return df.Date(tm, StyleFull) }, "2026年10月17日土曜日"},
+		{"hi", func(df DateFormat) string { return df.Date(tm, StyleLong) }, "17 अक्तूबर 2026"},
+		{"ar", func(df DateFormat) string { return df.Date(tm, StyleLong) }, "17 أكتوبر 2026"},
+		{"sr", func(df DateFormat) string { return df.Date(tm, StyleLong) }, "17. октобар 2026."},
+		{"sr-Latn", func(df DateFormat) string { return df.Date(tm, StyleLong) }, "17. oktobar 2026."},
+		{"xx", func(df DateFormat) string { return df.Date(tm, StyleMedium) }, "Oct 17, 2026"},
+		{"en", func(df DateFormat) string { return df.Pattern(tm, "EEE, d MMM yyyy HH:mm:ss 'o''clock'") }, "Sat, 17 Oct 2026 15:04:05 o'clock"},
+		{"en", func(df DateFormat) string { return df.Pattern(tm, "y G") }, "2026 AD"},
+		{"de", func(df DateFormat) string { return df.Pattern(tm, "y G") }, "2026 n. Chr."},
+		{"ru", func(df DateFormat) string { return df.Pattern(tm.AddDate(-2100, 0, 0), "G") }, "до н. э."},
+	}
+
+	for _, tt := range tests {
+		if got := tt.format(r.Dates(r.Parse(tt.code))); got != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.code, tt.expected, got)
+		}
+	}
+}
+
+func TestDateFormat_Skeleton(t *testing.T) {
+	r := NewLanguageRegistry()
+	tm := time.Date(2026, 10, 17, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		code     string
+		skeleton string
+		expected string
+	}{
+		{"en", "yMMMd", "Oct 17, 2026"},
+		{"de", "yMMMd", "17. Okt. 2026"},
+		{"cs", "yMMMM", "říjen 2026"},
+		{"ru", "yMMMM", "октябрь 2026 г."},
+		{"pl", "MMMMd", "17 października"},
+		{"ja", "hm", "午後3:04"},
+		{"fr", "hm", "3:04 PM"},
+	}
+
+	for _, tt := range tests {
+		got, err := r.Dates(r.Parse(tt.code)).Skeleton(tm, tt.skeleton)
+		if err != nil || got != tt.expected {
+			t.Errorf("%s %s: expected %q, got %q, %v", tt.code, tt.skeleton, tt.expected, got, err)
+		}
+	}
+
+	if _, err := r.Dates(r.Parse("en")).Skeleton(tm, "GGGGy"); !errors.Is(err, ErrUnsupportedSkeleton) {
+		t.Errorf("expected ErrUnsupportedSkeleton, got %v", err)
+	}
+}
+
+func TestDateFormat_Relative(t *testing.T) {
+	r := NewLanguageRegistry()
+
+	tests := []struct {
+		code     string
+		d        time.Duration
+		expected string
+	}{
+		{"en", -3*time.Hour - 20*time.Minute, "3 hours ago"},
+		{"en", time.Hour, "in 1 hour"},
+		{"en", 48 * time.Hour, "in 2 days"},
+		{"en", -10 * time.Second, "10 seconds ago"},
+		{"en", -400 * 24 * time.Hour, "1 year ago"},
+		{"cs", -3 * time.Hour, "před 3 hodinami"},
+		{"cs", -time.Hour, "před 1 hodinou"},
+		{"cs", 5 * time.Minute, "za 5 minut"},
+		{"ru", -21 * 24 * time.Hour, "3 недели назад"},
+		{"ru", -5 * 24 * time.Hour, "5 дней назад"},
+		{"fr", 60 * 24 * time.Hour, "dans 2 mois"},
+		{"ja", -2 * time.Minute, "2 分前"},
+	}
+
+	for _, tt := range tests {
+		if got := r.Dates(r.Parse(tt.code)).RelativeTime(tt.d); got != tt.expected {
+			t.Errorf("%s %v: expected %q, got %q", tt.code, tt.d, tt.expected, got)
+		}
+	}
+
+	if got := r.Dates(r.Parse("en")).Relative(-1500, RelativeDay); got != "1,500 days ago" {
+		t.Errorf("expected '1,500 days ago', got %q", got)
+	}
+}
+
+func TestDateFormat_FallbackChain(t *testing.T) {
+	r := NewLanguageRegistry()
+	sk := r.Parse("sk")
+	if err := r.SetFallback(sk, r.Parse("cs")); err != nil {
+		t.Fatalf("SetFallback failed: %v", err)
+	}
+
+	tm := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	if got := r.Dates(sk).Date(tm, StyleLong); got != "17. října 2026" {
+		t.Errorf("expected cs data for sk, got %q", got)
+	}
+
+	tc := NewContainer(WithLanguageRegistry(r))
+	if got := tc.Lang(sk).Dates().RelativeTime(-2 * time.Hour); got != "před 2 hodinami" {
+		t.Errorf("expected cs data for sk, got %q", got)
+	}
+}
+
+func TestDateFormat_ParentLocale(t *testing.T) {
+	r := NewLanguageRegistry()
+	tm := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		code     string
+		expected string
+	}{
+		{"de-CH", "17. Oktober 2026"},
+		{"sr-Latn-RS", "17. oktobar 2026."},
+		{"ar-EG", "17 أكتوبر 2026"},
+		{"hi-IN", "17 अक्तूबर 2026"},
+	}
+
+	for _, tt := range tests {
+		if got := r.Dates(r.Parse(tt.code)).Date(tm, StyleLong); got != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.code, tt.expected, got)
+		}
+	}
+
+	if got := r.Dates(r.Parse("sr-Latn")).Relative(-3, RelativeDay); got != "pre 3 dana" {
+		t.Errorf("expected sr-Latn relative time, got %q", got)
+	}
+}
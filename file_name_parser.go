@@ -5,7 +5,13 @@ import (
 	"strings"
 )
 
-type DefaultFilenameParser struct{}
+// DefaultFilenameParser parses file names like en-US.customer1.t18n.
+type DefaultFilenameParser struct {
+	// Registry receives parsed languages. If Registry is nil, the
+	// registry of the container is used, or the default registry if
+	// the parser is used outside of a container.
+	Registry *LanguageRegistry
+}
 
 var _ FilenameParser = (*DefaultFilenameParser)(nil)
 
@@ -22,7 +28,7 @@ func (DefaultFilenameParser) ExtractFilename(fullname string) (string, error) {
 // Example:
 // ParseFileName("en.t18n") returns English, ""
 // ParseFileName("en.grid.t18n") returns English, "grid"
-func (DefaultFilenameParser) ParseFilename(filename string) (li Language, suffix string) {
+func (p DefaultFilenameParser) ParseFilename(filename string) (li Language, suffix string) {
 	from := strings.Index(filename, ".")
 	to := strings.LastIndex(filename, ".")
 	if from == -1 && to == -1 {
@@ -30,9 +36,14 @@ func (DefaultFilenameParser) ParseFilename(filename string) (li Language, suffix
 		return Unknown, ""
 	}
 
+	r := p.Registry
+	if r == nil {
+		r = defaultRegistry
+	}
+
 	if from == to {
-		return Parse(filename[0:from]), ""
+		return r.Parse(filename[0:from]), ""
 	}
 
-	return Parse(filename[0:from]), filename[from+1 : to]
+	return r.Parse(filename[0:from]), filename[from+1 : to]
 }
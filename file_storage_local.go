@@ -3,11 +3,20 @@ package i18n
 import (
 	"os"
 	"path/filepath"
+	"sync"
 )
 
 // LocalFileStorage implements the FileStorager interface for local files.
 type LocalFileStorage struct {
-	names []string
+	mux           sync.RWMutex
+	names         []string
+	registrations []fileRegistration
+}
+
+// fileRegistration holds arguments of RegisterFiles call.
+type fileRegistration struct {
+	mask  string
+	paths []string
 }
 
 var _ FileStorager = (*LocalFileStorage)(nil)
@@ -22,17 +31,64 @@ func (s *LocalFileStorage) ReadFile(fullname string) ([]byte, error) {
 }
 
 func (s *LocalFileStorage) RegisteredFilenames() []string {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
 	return s.names
 }
 
 // RegisterFiles registers files by mask in the directories specified by paths.
 func (s *LocalFileStorage) RegisterFiles(mask string, paths ...string) error {
 
+	names, err := scanFiles(mask, paths...)
+	if err != nil {
+		return err
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.names = append(s.names, names...)
+	s.registrations = append(s.registrations, fileRegistration{mask: mask, paths: paths})
+	return nil
+}
+
+// Refresh scans directories registered by RegisterFiles again, so added
+// files are registered and removed files are unregistered.
+// On error registered files stay unchanged.
+func (s *LocalFileStorage) Refresh() error {
+
+	regs := s.fileRegistrations()
+
+	var names []string
+	for _, reg := range regs {
+		x, err := scanFiles(reg.mask, reg.paths...)
+		if err != nil {
+			return err
+		}
+		names = append(names, x...)
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.names = names
+	return nil
+}
+
+// fileRegistrations returns copy of registrations.
+func (s *LocalFileStorage) fileRegistrations() []fileRegistration {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return append([]fileRegistration(nil), s.registrations...)
+}
+
+// scanFiles returns files by mask in the directories specified by paths.
+func scanFiles(mask string, paths ...string) ([]string, error) {
+
+	var res []string
 	for _, dir := range paths {
 
 		dirEntries, err := os.ReadDir(dir)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		for _, de := range dirEntries {
@@ -40,23 +96,30 @@ func (s *LocalFileStorage) RegisterFiles(mask string, paths ...string) error {
 				continue
 			}
 
-			if len(mask) > 0 && mask != "*" {
-				ok, err := filepath.Match(mask, de.Name())
-				if err != nil {
-					return err
-				}
-				if !ok {
-					continue
-				}
+			ok, err := matchMask(mask, de.Name())
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
 			}
 
 			fi, err := de.Info()
 			if err != nil {
-				return err
+				return nil, err
 			}
 
-			s.names = append(s.names, filepath.Join(dir, fi.Name()))
+			res = append(res, filepath.Join(dir, fi.Name()))
 		}
 	}
-	return nil
+	return res, nil
+}
+
+// matchMask reports whether name matches the mask.
+// Empty mask and "*" match any name.
+func matchMask(mask, name string) (bool, error) {
+	if len(mask) == 0 || mask == "*" {
+		return true, nil
+	}
+	return filepath.Match(mask, name)
 }
@@ -0,0 +1,208 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrInvalidTemplate is returned if a value has unbalanced braces
+	// or an empty placeholder.
+	ErrInvalidTemplate = errors.New("invalid template")
+
+	// ErrMissingArgument is returned if a placeholder has no argument.
+	ErrMissingArgument = errors.New("missing argument")
+
+	// ErrExtraArgument is returned if an argument is not used by the template.
+	ErrExtraArgument = errors.New("unused argument")
+)
+
+// templatePart is a literal text or a named placeholder.
+type templatePart struct {
+	text        string
+	placeholder bool
+}
+
+// template is a parsed value with named placeholders like "Hello, {name}".
+type template struct {
+	parts []templatePart
+	names map[string]bool
+}
+
+// templateEntry is a cached result of parseTemplate.
+type templateEntry struct {
+	tmpl *template
+	err  error
+}
+
+// parseTemplate parses named placeholders in s. "{{" and "}}" are
+// escaped braces.
+func parseTemplate(s string) (*template, error) {
+	res := template{names: make(map[string]bool)}
+
+	var text strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '{' && i+1 < len(s) && s[i+1] == '{',
+			c == '}' && i+1 < len(s) && s[i+1] == '}':
+			text.WriteByte(c)
+			i++
+		case c == '{':
+			end := strings.IndexByte(s[i+1:], '}')
+			if end == -1 {
+				return nil, fmt.Errorf("%w: unclosed placeholder at %d", ErrInvalidTemplate, i)
+			}
+			name := strings.TrimSpace(s[i+1 : i+1+end])
+			if name == "" || strings.ContainsRune(name, '{') {
+				return nil, fmt.Errorf("%w: invalid placeholder at %d", ErrInvalidTemplate, i)
+			}
+			if text.Len() > 0 {
+				res.parts = append(res.parts, templatePart{text: text.String()})
+				text.Reset()
+			}
+			res.parts = append(res.parts, templatePart{text: name, placeholder: true})
+			res.names[name] = true
+			i += end + 1
+		case c == '}':
+			return nil, fmt.Errorf("%w: unexpected '}' at %d", ErrInvalidTemplate, i)
+		default:
+			text.WriteByte(c)
+		}
+	}
+
+	if text.Len() > 0 {
+		res.parts = append(res.parts, templatePart{text: text.String()})
+	}
+	return &res, nil
+}
+
+// execute substitutes placeholders by args. Placeholders without
+// argument are kept as is, like "{name}". The error lists missing
+// and unused arguments.
+func (t *template) execute(args map[string]any) (string, error) {
+	var (
+		sb      strings.Builder
+		missing []string
+	)
+
+	for _, p := range t.parts {
+		if !p.placeholder {
+			sb.WriteString(p.text)
+			continue
+		}
+		v, ok := args[p.text]
+		if !ok {
+			sb.WriteString("{" + p.text + "}")
+			if !containsString(missing, p.text) {
+				missing = append(missing, p.text)
+			}
+			continue
+		}
+		sb.WriteString(fmt.Sprint(v))
+	}
+
+	var extra []string
+	for name := range args {
+		if !t.names[name] {
+			extra = append(extra, name)
+		}
+	}
+
+	return sb.String(), argumentsError(missing, extra)
+}
+
+func containsString(a []string, s string) bool {
+	for i := range a {
+		if a[i] == s {
+			return true
+		}
+	}
+	return false
+}
+
+func argumentsError(missing, extra []string) error {
+	var errs []error
+	if len(missing) > 0 {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrMissingArgument, strings.Join(missing, ", ")))
+	}
+	if len(extra) > 0 {
+		sort.Strings(extra)
+		errs = append(errs, fmt.Errorf("%w: %s", ErrExtraArgument, strings.Join(extra, ", ")))
+	}
+	return errors.Join(errs...)
+}
+
+// Format returns the value of the key with named placeholders like
+// "Hello, {name}" replaced by args. Use "{{" and "}}" for literal braces.
+//
+// Format never panics. Missing arguments are left in the result as
+// placeholders, and the returned error wraps ErrMissingArgument and/or
+// ErrExtraArgument. If the value is not a valid template, the value
+// is returned as is with an error wrapping ErrInvalidTemplate.
+// If the key is not found, Value(key) is returned.
+//
+// Parsed templates are cached until the container is reloaded.
+func (tr TranslationRequest) Format(key string, args map[string]any) (string, error) {
+	if tr.tc == nil {
+		return key, nil
+	}
+
+	snap := tr.tc.snapshot()
+	item, li, ok := tr.lookupIn(snap, key, nil)
+	tr.report(key, li, ok)
+	if !ok {
+		return tr.notFound(key).Value, nil
+	}
+
+	var e *templateEntry
+	if v, ok := snap.templates.Load(item); ok {
+		e = v.(*templateEntry)
+	} else {
+		e = &templateEntry{}
+		e.tmpl, e.err = parseTemplate(item.Value)
+		snap.templates.Store(item, e)
+	}
+
+	if e.err != nil {
+		return item.Value, fmt.Errorf("key %q: %w", key, e.err)
+	}
+
+	res, err := e.tmpl.execute(args)
+	if err != nil {
+		return res, fmt.Errorf("key %q: %w", key, err)
+	}
+	return res, nil
+}
+
+// FormatArgs is like Format, but the arguments are given as name-value
+// pairs: FormatArgs("Greeting", "name", "Bob", "count", 3).
+func (tr TranslationRequest) FormatArgs(key string, kv ...any) (string, error) {
+	args, argErr := pairsToArgs(kv)
+	res, err := tr.Format(key, args)
+	return res, errors.Join(err, argErr)
+}
+
+// pairsToArgs converts name-value pairs to the map of arguments.
+// A pair with a non-string name or a name without a value is reported
+// in the error and skipped.
+func pairsToArgs(kv []any) (map[string]any, error) {
+	args := make(map[string]any, len(kv)/2)
+
+	var errs []error
+	for i := 0; i < len(kv); i += 2 {
+		name, ok := kv[i].(string)
+		if !ok {
+			errs = append(errs, fmt.Errorf("argument %d: name is %T, not string", i, kv[i]))
+			continue
+		}
+		if i+1 == len(kv) {
+			errs = append(errs, fmt.Errorf("%w: value of %q", ErrMissingArgument, name))
+			continue
+		}
+		args[name] = kv[i+1]
+	}
+	return args, errors.Join(errs...)
+}
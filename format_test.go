@@ -0,0 +1,93 @@
+package i18n
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseTemplate(t *testing.T) {
+
+	tests := []struct {
+		src      string
+		args     map[string]any
+		expected string
+		err      error
+	}{
+		{"Hello, {name}!", map[string]any{"name": "Bob"}, "Hello, Bob!", nil},
+		{"{ a }+{b}={c}", map[string]any{"a": 1, "b": 2, "c": 3}, "1+2=3", nil},
+		{"{{name}} is {name}", map[string]any{"name": "x"}, "{name} is x", nil},
+		{"no placeholders", nil, "no placeholders", nil},
+		{"Hello, {name} and {name}", nil, "Hello, {name} and {name}", ErrMissingArgument},
+		{"Hello", map[string]any{"name": "Bob"}, "Hello", ErrExtraArgument},
+		{"Hello, {name", nil, "", ErrInvalidTemplate},
+		{"Hello, {}", nil, "", ErrInvalidTemplate},
+		{"Hello, name}", nil, "", ErrInvalidTemplate},
+	}
+
+	for _, tt := range tests {
+		tmpl, err := parseTemplate(tt.src)
+		if err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Errorf("%q: expected error %v, got %v", tt.src, tt.err, err)
+			}
+			continue
+		}
+
+		got, err := tmpl.execute(tt.args)
+		if got != tt.expected {
+			t.Errorf("%q: expected '%s', got '%s'", tt.src, tt.expected, got)
+		}
+		if tt.err == nil && err != nil || tt.err != nil && !errors.Is(err, tt.err) {
+			t.Errorf("%q: expected error %v, got %v", tt.src, tt.err, err)
+		}
+	}
+}
+
+func TestTranslationRequest_Format(t *testing.T) {
+	r := NewLanguageRegistry()
+	en := r.Parse("en")
+	de := r.Parse("de")
+
+	tc := NewContainer(
+		WithLanguageRegistry(r),
+		WithPrimaryLanguage(en),
+		WithStorage(mapStorage{
+			"en.t18n": "Greeting=Hello, {name}!\nBroken=Hello, {name\n",
+			"de.t18n": "Greeting=Hallo, {name}!\n",
+		}),
+	)
+	if err := tc.ReadRegisteredFiles(); err != nil {
+		t.Fatalf("ReadRegisteredFiles failed: %v", err)
+	}
+
+	got, err := tc.Lang(de).Format("Greeting", map[string]any{"name": "Anna"})
+	if err != nil || got != "Hallo, Anna!" {
+		t.Errorf("expected 'Hallo, Anna!', got '%s', %v", got, err)
+	}
+
+	// cached template
+	got, err = tc.Lang(de).FormatArgs("Greeting", "name", "Jan")
+	if err != nil || got != "Hallo, Jan!" {
+		t.Errorf("expected 'Hallo, Jan!', got '%s', %v", got, err)
+	}
+
+	got, err = tc.Lang(en).FormatArgs("Greeting", "name", "Bob", "age", 30)
+	if got != "Hello, Bob!" || !errors.Is(err, ErrExtraArgument) {
+		t.Errorf("expected 'Hello, Bob!' and ErrExtraArgument, got '%s', %v", got, err)
+	}
+
+	got, err = tc.Lang(en).FormatArgs("Greeting", "name")
+	if got != "Hello, {name}!" || !errors.Is(err, ErrMissingArgument) {
+		t.Errorf("expected 'Hello, {name}!' and ErrMissingArgument, got '%s', %v", got, err)
+	}
+
+	got, err = tc.Lang(en).Format("Broken", nil)
+	if got != "Hello, {name" || !errors.Is(err, ErrInvalidTemplate) {
+		t.Errorf("expected raw value and ErrInvalidTemplate, got '%s', %v", got, err)
+	}
+
+	got, err = tc.Lang(en).Format("Unknown", nil)
+	if got != "Unknown" || err != nil {
+		t.Errorf("expected 'Unknown', got '%s', %v", got, err)
+	}
+}
@@ -0,0 +1,474 @@
+package i18n
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// defaultPluralForms is used for files without the Plural-Forms header.
+const defaultPluralForms = "nplurals=2; plural=(n != 1);"
+
+// gettextEntry is a message of a PO or MO file.
+type gettextEntry struct {
+	line     int // line of msgid in a PO file
+	context  string
+	id       string
+	plural   bool
+	strs     []string
+	comments []string
+}
+
+// gettextItems converts messages to items. The header entry (empty msgid)
+// provides the language and plural forms, untranslated messages are
+// skipped.
+//
+// The translation of a plural message is assigned to CLDR plural
+// categories of the language: msgstr[i] becomes the variant of the
+// category of the first number n the plural expression maps to i. Value of
+// the item is the variant "other".
+func gettextItems(entries []gettextEntry) ([]Item, error) {
+
+	var header map[string]string
+	for i := range entries {
+		if e := &entries[i]; e.id == "" && e.context == "" && len(e.strs) > 0 {
+			header = parseGettextHeader(e.strs[0])
+			break
+		}
+	}
+
+	forms := header["Plural-Forms"]
+	if forms == "" {
+		forms = defaultPluralForms
+	}
+	gp, err := parsePluralForms(forms)
+	if err != nil {
+		return nil, err
+	}
+	byCategory, _ := gp.categories(gettextLocale(header["Language"]))
+
+	var res []Item
+	for i := range entries {
+		e := &entries[i]
+		if e.id == "" && e.context == "" {
+			continue
+		}
+
+		item := Item{Key: e.id, Context: e.context, Hint: strings.Join(e.comments, "\n")}
+		if !e.plural {
+			if len(e.strs) == 0 || e.strs[0] == "" {
+				continue
+			}
+			item.Value = e.strs[0]
+			res = append(res, item)
+			continue
+		}
+
+		for cat, idx := range byCategory {
+			if idx < len(e.strs) && e.strs[idx] != "" {
+				item.SetVariant(cat.String(), e.strs[idx])
+			}
+		}
+		if item.Variants == nil {
+			continue
+		}
+		item.Value, _ = item.Variant(PluralOther.String())
+		res = append(res, item)
+	}
+	return res, nil
+}
+
+// parseGettextHeader parses "Name: value" lines of the header entry.
+func parseGettextHeader(s string) map[string]string {
+	res := make(map[string]string)
+	for _, line := range strings.Split(s, "\n") {
+		name, value, ok := strings.Cut(line, ":")
+		if ok {
+			res[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+	}
+	return res
+}
+
+// gettextLocale converts a gettext locale like pt_BR, de_DE.UTF-8 or
+// sr_RS@latin to a BCP 47 code: pt-BR, de-DE, sr-Latn-RS.
+func gettextLocale(locale string) string {
+	locale, modifier, _ := strings.Cut(locale, "@")
+	locale, _, _ = strings.Cut(locale, ".")
+	locale = strings.ReplaceAll(locale, "_", "-")
+
+	script := ""
+	switch strings.ToLower(modifier) {
+	case "latin":
+		script = "Latn"
+	case "cyrillic":
+		script = "Cyrl"
+	}
+	if script == "" {
+		return locale
+	}
+
+	lang, region, ok := strings.Cut(locale, "-")
+	if !ok {
+		return lang + "-" + script
+	}
+	return lang + "-" + script + "-" + region
+}
+
+// gettextPlural is a parsed Plural-Forms header.
+type gettextPlural struct {
+	n    int
+	expr pluralExpr
+}
+
+// pluralExpr evaluates a C plural expression of gettext.
+type pluralExpr func(n uint64) uint64
+
+// parsePluralForms parses the value of the Plural-Forms header:
+//
+//	nplurals=3; plural=(n==1) ? 0 : (n>=2 && n<=4) ? 1 : 2;
+func parsePluralForms(s string) (gettextPlural, error) {
+	var (
+		res         gettextPlural
+		nOk, exprOk bool
+	)
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(name) {
+		case "nplurals":
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || n < 1 {
+				return res, fmt.Errorf("invalid nplurals in Plural-Forms %q", s)
+			}
+			res.n, nOk = n, true
+		case "plural":
+			p := pluralExprParser{s: value}
+			expr, err := p.parse()
+			if err != nil {
+				return res, fmt.Errorf("invalid plural in Plural-Forms %q: %w", s, err)
+			}
+			res.expr, exprOk = expr, true
+		}
+	}
+	if !nOk || !exprOk {
+		return res, fmt.Errorf("invalid Plural-Forms %q", s)
+	}
+	return res, nil
+}
+
+// pluralSamples is the number of integers sampled to map msgstr indexes
+// to CLDR plural categories.
+const pluralSamples = 1000
+
+// categories maps msgstr indexes to CLDR plural categories of the
+// language code. Rules of English are used if the code has no rules.
+//
+// A category gets the index of the first integer of the category,
+// an index gets the category of the first integer mapped to it. "other"
+// gets the last index if no integer is mapped to it, an index no
+// integer is mapped to gets "other".
+func (gp gettextPlural) categories(code string) (byCategory map[PluralCategory]int, byIndex []PluralCategory) {
+	set := cardinalRuleSet()
+	if set.rules(code) == nil {
+		code = "en"
+	}
+
+	byCategory = make(map[PluralCategory]int)
+	byIndex = make([]PluralCategory, gp.n)
+	seen := make([]bool, gp.n)
+	for n := uint64(0); n < pluralSamples; n++ {
+		idx := gp.expr(n)
+		if idx >= uint64(gp.n) {
+			continue
+		}
+
+		cat := pluralCategory(set, code, n)
+		if _, ok := byCategory[cat]; !ok {
+			byCategory[cat] = int(idx)
+		}
+		if !seen[idx] {
+			seen[idx] = true
+			byIndex[idx] = cat
+		}
+	}
+
+	if _, ok := byCategory[PluralOther]; !ok {
+		byCategory[PluralOther] = gp.n - 1
+	}
+	for i := range byIndex {
+		if !seen[i] {
+			byIndex[i] = PluralOther
+		}
+	}
+	return byCategory, byIndex
+}
+
+// pluralExprParser parses C expressions of the Plural-Forms header: the
+// variable n, integers, operators ?:, ||, &&, ==, !=, <, <=, >, >=, +,
+// -, *, /, %, ! and parentheses.
+type pluralExprParser struct {
+	s   string
+	pos int
+}
+
+func (p *pluralExprParser) parse() (pluralExpr, error) {
+	expr, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpaces(); p.pos != len(p.s) {
+		return nil, fmt.Errorf("unexpected %q at %d", p.s[p.pos:], p.pos)
+	}
+	return expr, nil
+}
+
+func (p *pluralExprParser) skipSpaces() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) != -1 {
+		p.pos++
+	}
+}
+
+// accept consumes the operator op.
+func (p *pluralExprParser) accept(op string) bool {
+	p.skipSpaces()
+	if !strings.HasPrefix(p.s[p.pos:], op) {
+		return false
+	}
+	// "<" is not "<=", "!" is not "!="
+	if len(op) == 1 && strings.IndexByte("<>!=", op[0]) != -1 &&
+		p.pos+1 < len(p.s) && p.s[p.pos+1] == '=' {
+		return false
+	}
+	p.pos += len(op)
+	return true
+}
+
+func (p *pluralExprParser) ternary() (pluralExpr, error) {
+	cond, err := p.binary(0)
+	if err != nil || !p.accept("?") {
+		return cond, err
+	}
+
+	yes, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	if !p.accept(":") {
+		return nil, fmt.Errorf("expected ':' at %d", p.pos)
+	}
+	no, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(n uint64) uint64 {
+		if cond(n) != 0 {
+			return yes(n)
+		}
+		return no(n)
+	}, nil
+}
+
+// pluralOperators holds binary operators by precedence, from the lowest.
+var pluralOperators = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<=", ">=", "<", ">"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *pluralExprParser) binary(level int) (pluralExpr, error) {
+	if level == len(pluralOperators) {
+		return p.unary()
+	}
+
+	x, err := p.binary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := ""
+		for _, o := range pluralOperators[level] {
+			if p.accept(o) {
+				op = o
+				break
+			}
+		}
+		if op == "" {
+			return x, nil
+		}
+
+		y, err := p.binary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		x = pluralBinary(op, x, y)
+	}
+}
+
+func pluralBinary(op string, x, y pluralExpr) pluralExpr {
+	b := func(v bool) uint64 {
+		if v {
+			return 1
+		}
+		return 0
+	}
+
+	switch op {
+	case "||":
+		return func(n uint64) uint64 { return b(x(n) != 0 || y(n) != 0) }
+	case "&&":
+		return func(n uint64) uint64 { return b(x(n) != 0 && y(n) != 0) }
+	case "==":
+		return func(n uint64) uint64 { return b(x(n) == y(n)) }
+	case "!=":
+		return func(n uint64) uint64 { return b(x(n) != y(n)) }
+	case "<":
+		return func(n uint64) uint64 { return b(x(n) < y(n)) }
+	case "<=":
+		return func(n uint64) uint64 { return b(x(n) <= y(n)) }
+	case ">":
+		return func(n uint64) uint64 { return b(x(n) > y(n)) }
+	case ">=":
+		return func(n uint64) uint64 { return b(x(n) >= y(n)) }
+	case "+":
+		return func(n uint64) uint64 { return x(n) + y(n) }
+	case "-":
+		return func(n uint64) uint64 { return x(n) - y(n) }
+	case "*":
+		return func(n uint64) uint64 { return x(n) * y(n) }
+	case "/":
+		return func(n uint64) uint64 {
+			if d := y(n); d != 0 {
+				return x(n) / d
+			}
+			return 0
+		}
+	}
+	// "%"
+	return func(n uint64) uint64 {
+		if d := y(n); d != 0 {
+			return x(n) % d
+		}
+		return 0
+	}
+}
+
+func (p *pluralExprParser) unary() (pluralExpr, error) {
+	if p.accept("!") {
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(n uint64) uint64 {
+			if x(n) == 0 {
+				return 1
+			}
+			return 0
+		}, nil
+	}
+
+	if p.accept("(") {
+		x, err := p.ternary()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("expected ')' at %d", p.pos)
+		}
+		return x, nil
+	}
+
+	if p.accept("n") {
+		return func(n uint64) uint64 { return n }, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+		p.pos++
+	}
+	if start == p.pos {
+		return nil, fmt.Errorf("unexpected %q at %d", p.s[p.pos:], p.pos)
+	}
+	v, err := strconv.ParseUint(p.s[start:p.pos], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return func(uint64) uint64 { return v }, nil
+}
+
+// GettextFilenameParser parses names of gettext files in the layout
+// {lang}/LC_MESSAGES/{domain}.po, a domain is loaded to the namespace of
+// the same name. Flat names like cs.po or pt_BR.mo are loaded to the
+// default namespace.
+//
+// LocalFileStorage does not scan directories recursively, thus each
+// LC_MESSAGES directory is registered:
+//
+//	fs.RegisterFiles("*.po", "locale/cs/LC_MESSAGES", "locale/de/LC_MESSAGES")
+//	tc := i18n.NewContainer(
+//		i18n.WithStorage(fs),
+//		i18n.WithFilenameParser(&i18n.GettextFilenameParser{DefaultDomain: "app"}),
+//		i18n.WithCustomFileParser(&i18n.POParser{}),
+//	)
+type GettextFilenameParser struct {
+	// Registry receives parsed languages. If Registry is nil, the
+	// registry of the container is used, or the default registry if
+	// the parser is used outside of a container.
+	Registry *LanguageRegistry
+
+	// DefaultDomain is the domain loaded to the default namespace "".
+	DefaultDomain string
+}
+
+var _ FilenameParser = (*GettextFilenameParser)(nil)
+
+// ExtractFilename returns "{lang}/LC_MESSAGES/{domain}.po" from the full
+// path in the gettext layout, the base name otherwise.
+func (GettextFilenameParser) ExtractFilename(fullname string) (string, error) {
+	parts := strings.Split(filepath.ToSlash(fullname), "/")
+	if n := len(parts); n >= 3 && parts[n-2] == "LC_MESSAGES" {
+		return strings.Join(parts[n-3:], "/"), nil
+	}
+	return parts[len(parts)-1], nil
+}
+
+// ParseFilename returns the language and namespace of the file name.
+//
+// Example:
+// ParseFilename("pt_BR/LC_MESSAGES/grid.po") returns pt-BR, "grid"
+// ParseFilename("sr@latin/LC_MESSAGES/app.mo") returns sr-Latn, "" if DefaultDomain is "app"
+// ParseFilename("cs.po") returns cs, ""
+func (p GettextFilenameParser) ParseFilename(filename string) (Language, string) {
+	r := p.Registry
+	if r == nil {
+		r = defaultRegistry
+	}
+
+	dir, name := path.Split(filename)
+	if ext := path.Ext(name); ext != "" {
+		name = strings.TrimSuffix(name, ext)
+	}
+
+	locale, domain := name, ""
+	if strings.HasSuffix(dir, "/LC_MESSAGES/") {
+		locale, _, _ = strings.Cut(dir, "/")
+		domain = name
+	}
+	if domain == p.DefaultDomain {
+		domain = ""
+	}
+
+	li := r.Parse(gettextLocale(locale))
+	if li == Unknown {
+		return Unknown, ""
+	}
+	return li, domain
+}
@@ -0,0 +1,90 @@
+package i18n
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidMO is returned for content which is not a valid MO file.
+var ErrInvalidMO = errors.New("invalid MO file")
+
+// moMagic is the magic number of MO files.
+const moMagic = 0x950412de
+
+// MOParser parses compiled gettext MO files, little and big endian.
+//
+// Messages are mapped to items like POParser does. MO files have no
+// comments, thus items have no hints.
+type MOParser struct{}
+
+var _ FileContentParser = (*MOParser)(nil)
+
+// ParseFileContent parses the content of a MO file.
+func (p *MOParser) ParseFileContent(data []byte) ([]Item, error) {
+
+	if len(data) < 20 {
+		return nil, fmt.Errorf("%w: too short", ErrInvalidMO)
+	}
+
+	var bo binary.ByteOrder = binary.LittleEndian
+	if bo.Uint32(data) != moMagic {
+		bo = binary.BigEndian
+		if bo.Uint32(data) != moMagic {
+			return nil, fmt.Errorf("%w: bad magic number", ErrInvalidMO)
+		}
+	}
+
+	if rev := bo.Uint32(data[4:]); rev>>16 > 1 {
+		return nil, fmt.Errorf("%w: unsupported revision %d.%d", ErrInvalidMO, rev>>16, rev&0xffff)
+	}
+
+	n := bo.Uint32(data[8:])
+	originals := bo.Uint32(data[12:])
+	translations := bo.Uint32(data[16:])
+
+	// both string tables shall fit into the content
+	for _, table := range []uint32{originals, translations} {
+		if uint64(table)+uint64(n)*8 > uint64(len(data)) {
+			return nil, fmt.Errorf("%w: string table out of range", ErrInvalidMO)
+		}
+	}
+
+	// str returns the i-th string of the table at the offset.
+	str := func(table, i uint32) (string, error) {
+		at := uint64(table) + uint64(i)*8
+		if at+8 > uint64(len(data)) {
+			return "", fmt.Errorf("%w: string table out of range", ErrInvalidMO)
+		}
+		length := uint64(bo.Uint32(data[at:]))
+		offset := uint64(bo.Uint32(data[at+4:]))
+		if offset+length > uint64(len(data)) {
+			return "", fmt.Errorf("%w: string %d out of range", ErrInvalidMO, i)
+		}
+		return string(data[offset : offset+length]), nil
+	}
+
+	entries := make([]gettextEntry, 0, n)
+	for i := uint32(0); i < n; i++ {
+		orig, err := str(originals, i)
+		if err != nil {
+			return nil, err
+		}
+		trans, err := str(translations, i)
+		if err != nil {
+			return nil, err
+		}
+
+		// the original is "msgctxt\x04msgid\x00msgid_plural"
+		var e gettextEntry
+		if ctx, id, ok := strings.Cut(orig, "\x04"); ok {
+			e.context, orig = ctx, id
+		}
+		e.id, _, e.plural = strings.Cut(orig, "\x00")
+		e.strs = strings.Split(trans, "\x00")
+		entries = append(entries, e)
+	}
+
+	return gettextItems(entries)
+}
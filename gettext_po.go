@@ -0,0 +1,379 @@
+package i18n
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// POParser parses gettext PO and POT files.
+//
+// Messages are mapped to items: msgid is the key, msgctxt the context,
+// msgstr the value and translator comments ("# ...") the hint:
+//
+//	# shown on the toolbar
+//	msgctxt "toolbar"
+//	msgid "Open"
+//	msgstr "Otevřít"
+//
+// Translations of plural messages become plural variants, see
+// Plural-Forms header, files without it use "nplurals=2; plural=(n != 1);".
+// Untranslated, fuzzy and obsolete messages are skipped, like msgfmt does.
+// Content is expected in UTF-8.
+type POParser struct{}
+
+var _ DiagnosticParser = (*POParser)(nil)
+
+// ParseFileContent parses the content of a PO file. Problems of the
+// content are ignored, see ParseFileDiagnostics.
+func (p *POParser) ParseFileContent(data []byte) ([]Item, error) {
+	items, _, err := p.ParseFileDiagnostics(data)
+	return items, err
+}
+
+// ParseFileDiagnostics is like ParseFileContent but returns problems of
+// the content as well: malformed lines, messages declared twice and
+// invalid UTF-8. Malformed messages are skipped, the last declaration of
+// a message wins.
+func (p *POParser) ParseFileDiagnostics(data []byte) ([]Item, Diagnostics, error) {
+
+	lines, err := readLines(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		entries []gettextEntry
+		diags   Diagnostics
+		cur     gettextEntry
+		fuzzy   bool
+		started bool // a keyword of the current entry is read
+		first   int  // line of the first keyword
+		broken  bool // the current entry is malformed
+
+		// target receives continuation strings: msgctxt, msgid,
+		// msgid_plural or msgstr[strIdx].
+		target string
+		strIdx int
+	)
+	declared := make(map[string]int) // message id -> line
+
+	malformed := func(ln int, format string, args ...any) {
+		diags = append(diags, Diagnostic{
+			Line:   ln,
+			Column: 1,
+			Err:    fmt.Errorf("%w: "+format, append([]any{ErrMalformedLine}, args...)...),
+		})
+		broken = true
+	}
+
+	flush := func() {
+		switch {
+		case !started:
+		case broken:
+		case cur.line == 0:
+			malformed(first, "message without msgid")
+		case len(cur.strs) == 0:
+			malformed(cur.line, "msgid %q without msgstr", cur.id)
+		case fuzzy && cur.id != "":
+		default:
+			id := itemID(cur.context, cur.id)
+			if prev, ok := declared[id]; ok {
+				diags = append(diags, Diagnostic{
+					Line:   cur.line,
+					Column: 1,
+					Err:    fmt.Errorf("%w: %q, first declared at line %d", ErrDuplicateKey, displayID(id), prev),
+				})
+			} else {
+				declared[id] = cur.line
+			}
+			entries = append(entries, cur)
+		}
+		cur, fuzzy, started, broken, target = gettextEntry{}, false, false, false, ""
+	}
+
+	for i, raw := range lines {
+		ln := i + 1
+		line := strings.TrimSpace(raw)
+
+		if !utf8.ValidString(raw) {
+			diags = append(diags, Diagnostic{
+				Line:   ln,
+				Column: invalidUTF8Column(raw),
+				Err:    ErrInvalidUTF8,
+			})
+		}
+
+		switch {
+		case line == "":
+			flush()
+			continue
+		case strings.HasPrefix(line, "#"):
+			if started {
+				flush()
+			}
+			switch {
+			case strings.HasPrefix(line, "#,"):
+				for _, flag := range strings.Split(line[2:], ",") {
+					if strings.TrimSpace(flag) == "fuzzy" {
+						fuzzy = true
+					}
+				}
+			case line == "#" || strings.HasPrefix(line, "# "):
+				cur.comments = append(cur.comments, strings.TrimPrefix(line[1:], " "))
+			}
+			// extracted comments, references, previous and obsolete
+			// messages are ignored
+			continue
+		case strings.HasPrefix(line, `"`):
+			s, err := unquotePO(line)
+			switch {
+			case err != nil:
+				malformed(ln, "%v", err)
+			case target == "":
+				malformed(ln, "unexpected string")
+			case target == "msgctxt":
+				cur.context += s
+			case target == "msgid":
+				cur.id += s
+			case target == "msgid_plural":
+				// the source plural form is not kept
+			default:
+				cur.strs[strIdx] += s
+			}
+			continue
+		}
+
+		keyword, rest, _ := strings.Cut(line, " ")
+		s, err := unquotePO(strings.TrimSpace(rest))
+
+		// a new message starts with msgctxt or msgid
+		if (keyword == "msgctxt" || keyword == "msgid") && len(cur.strs) > 0 {
+			flush()
+		}
+		if err != nil {
+			malformed(ln, "%v", err)
+			continue
+		}
+		if !started {
+			started, first = true, ln
+		}
+
+		switch {
+		case keyword == "msgctxt":
+			cur.context = s
+		case keyword == "msgid":
+			cur.id, cur.line = s, ln
+		case keyword == "msgid_plural":
+			cur.plural = true
+		case keyword == "msgstr":
+			strIdx = len(cur.strs)
+			cur.strs = append(cur.strs, s)
+		case strings.HasPrefix(keyword, "msgstr[") && strings.HasSuffix(keyword, "]"):
+			idx, err := strconv.Atoi(keyword[len("msgstr[") : len(keyword)-1])
+			if err != nil || idx < 0 || idx > 100 {
+				malformed(ln, "invalid keyword %q", keyword)
+				continue
+			}
+			for len(cur.strs) <= idx {
+				cur.strs = append(cur.strs, "")
+			}
+			strIdx = idx
+			cur.strs[idx] = s
+		default:
+			malformed(ln, "unknown keyword %q", keyword)
+			continue
+		}
+		target = keyword
+		if strings.HasPrefix(keyword, "msgstr") {
+			target = "msgstr"
+		}
+	}
+	flush()
+
+	items, err := gettextItems(entries)
+	if err != nil {
+		return nil, nil, err
+	}
+	return items, diags, nil
+}
+
+// unquotePO returns the content of a C string literal.
+func unquotePO(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", fmt.Errorf("expected quoted string, got %q", s)
+	}
+	res, err := strconv.Unquote(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid string %s", s)
+	}
+	return res, nil
+}
+
+// EncodePO writes items as a PO file of the language code.
+//
+// The header declares the language and Plural-Forms of common languages,
+// other languages get "nplurals=2; plural=(n != 1);". Items having plural
+// variants are written as plural messages, msgstr[i] is the variant of the
+// category of the first number n the plural expression maps to i. Other
+// variants, like genders, can't be expressed in gettext and are omitted.
+// Hints are written as translator comments.
+func EncodePO(w io.Writer, items []Item, code string) error {
+
+	forms := poPluralForms(code)
+	gp, err := parsePluralForms(forms)
+	if err != nil {
+		return err
+	}
+	_, byIndex := gp.categories(code)
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString("msgid \"\"\nmsgstr \"\"\n")
+	for _, h := range []string{
+		"Language: " + strings.ReplaceAll(code, "-", "_"),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"Content-Transfer-Encoding: 8bit",
+		"Plural-Forms: " + forms,
+	} {
+		bw.WriteString(quotePO(h+"\n") + "\n")
+	}
+
+	for i := range items {
+		item := &items[i]
+		if item.Key == "" {
+			return fmt.Errorf("item %d: %w", i, ErrEmptyKey)
+		}
+
+		bw.WriteByte('\n')
+		if hint := strings.TrimSpace(item.Hint); hint != "" {
+			for _, line := range strings.Split(hint, "\n") {
+				bw.WriteString(strings.TrimRight("# "+line, " ") + "\n")
+			}
+		}
+		if item.Context != "" {
+			writePOString(bw, "msgctxt", item.Context)
+		}
+		writePOString(bw, "msgid", item.Key)
+
+		if !hasPluralVariants(item) {
+			writePOString(bw, "msgstr", item.Value)
+			continue
+		}
+
+		writePOString(bw, "msgid_plural", item.Key)
+		for idx, cat := range byIndex {
+			s, ok := item.Variant(cat.String())
+			if !ok {
+				s, ok = item.Variant(PluralOther.String())
+			}
+			if !ok {
+				s = item.Value
+			}
+			writePOString(bw, "msgstr["+strconv.Itoa(idx)+"]", s)
+		}
+	}
+	return bw.Flush()
+}
+
+// hasPluralVariants reports if the item has variants of plural categories.
+func hasPluralVariants(item *Item) bool {
+	for v := range item.variants() {
+		if _, ok := ParsePluralCategory(v); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// writePOString writes the keyword and the string. Multi-line strings are
+// split after new lines, as xgettext does.
+func writePOString(w *bufio.Writer, keyword, s string) {
+	if !strings.Contains(strings.TrimSuffix(s, "\n"), "\n") {
+		w.WriteString(keyword + " " + quotePO(s) + "\n")
+		return
+	}
+
+	w.WriteString(keyword + " \"\"\n")
+	for s != "" {
+		line := s
+		if i := strings.IndexByte(s, '\n'); i != -1 {
+			line = s[:i+1]
+		}
+		w.WriteString(quotePO(line) + "\n")
+		s = s[len(line):]
+	}
+}
+
+// quotePO returns s as a C string literal.
+func quotePO(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '\\', '"':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '\r':
+			sb.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&sb, `\%03o`, r)
+				continue
+			}
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// poPluralFormsData holds Plural-Forms of languages written by EncodePO.
+var poPluralFormsData = map[string]string{
+	"ja": "nplurals=1; plural=0;",
+	"ko": "nplurals=1; plural=0;",
+	"zh": "nplurals=1; plural=0;",
+	"vi": "nplurals=1; plural=0;",
+	"th": "nplurals=1; plural=0;",
+	"id": "nplurals=1; plural=0;",
+
+	"fr":    "nplurals=2; plural=(n > 1);",
+	"pt":    "nplurals=2; plural=(n > 1);",
+	"pt-PT": "nplurals=2; plural=(n != 1);",
+
+	"cs": "nplurals=3; plural=(n==1) ? 0 : (n>=2 && n<=4) ? 1 : 2;",
+	"sk": "nplurals=3; plural=(n==1) ? 0 : (n>=2 && n<=4) ? 1 : 2;",
+	"pl": "nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"ru": "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"uk": "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"be": "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"hr": "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"sr": "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"lt": "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"lv": "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n != 0 ? 1 : 2);",
+	"ro": "nplurals=3; plural=(n==1 ? 0 : (n==0 || (n%100 > 0 && n%100 < 20)) ? 1 : 2);",
+	"sl": "nplurals=4; plural=(n%100==1 ? 0 : n%100==2 ? 1 : n%100==3 || n%100==4 ? 2 : 3);",
+	"ga": "nplurals=5; plural=(n==1 ? 0 : n==2 ? 1 : n<7 ? 2 : n<11 ? 3 : 4);",
+	"ar": "nplurals=6; plural=(n==0 ? 0 : n==1 ? 1 : n==2 ? 2 : n%100>=3 && n%100<=10 ? 3 : n%100>=11 ? 4 : 5);",
+}
+
+// poPluralForms returns Plural-Forms of the language code or its parents.
+func poPluralForms(code string) string {
+	for {
+		if forms, ok := poPluralFormsData[code]; ok {
+			return forms
+		}
+		parent, ok := parentCode(code)
+		if !ok {
+			return defaultPluralForms
+		}
+		code = parent
+	}
+}
@@ -0,0 +1,382 @@
+package i18n
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+const testPO = `# Czech translation.
+msgid ""
+msgstr ""
+"Language: cs_CZ\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Plural-Forms: nplurals=3; plural=(n==1) ? 0 : (n>=2 && n<=4) ? 1 : 2;\n"
+
+# save button
+#. extracted comment
+#: main.go:12
+msgid "Save"
+msgstr "Uložit"
+
+msgctxt "toolbar"
+msgid "Open"
+msgstr "Otevřít"
+
+# shown in the list
+msgid "# file"
+msgid_plural "# files"
+msgstr[0] "# soubor"
+msgstr[1] "# soubory"
+msgstr[2] "# souborů"
+
+msgid "Terms"
+msgstr ""
+"First line.\n"
+"Second \"line\"."
+msgid "Untranslated"
+msgstr ""
+
+#, fuzzy
+msgid "Fuzzy"
+msgstr "Nejasné"
+
+#~ msgid "Obsolete"
+#~ msgstr "Zastaralé"
+`
+
+func TestPOParser(t *testing.T) {
+	var p POParser
+	items, diags, err := p.ParseFileDiagnostics([]byte(testPO))
+	if err != nil || len(diags) != 0 {
+		t.Fatalf("unexpected error: %v, %v", err, diags)
+	}
+
+	expected := []Item{
+		{Key: "Save", Value: "Uložit", Hint: "save button"},
+		{Key: "Open", Context: "toolbar", Value: "Otevřít"},
+		{Key: "# file", Value: "# souborů", Hint: "shown in the list", Variants: &Variants{
+			"one":   "# soubor",
+			"few":   "# soubory",
+			"other": "# souborů",
+		}},
+		{Key: "Terms", Value: "First line.\nSecond \"line\"."},
+	}
+	if !reflect.DeepEqual(items, expected) {
+		t.Errorf("expected %+v, got %+v", expected, items)
+	}
+}
+
+func TestPOParser_Diagnostics(t *testing.T) {
+	data := "msgid \"Save\"\nmsgstr \"Uložit\"\n\nmsgid \"Open\"\nmsgstr Otevřít\n\nmsgid \"Save\"\nmsgstr \"Uložit!\"\n\nmsgid \"Exit\"\n"
+
+	var p POParser
+	items, diags, err := p.ParseFileDiagnostics([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 2 || items[1].Value != "Uložit!" {
+		t.Errorf("unexpected items %+v", items)
+	}
+
+	if len(diags) != 3 {
+		t.Fatalf("expected 3 diagnostics, got %v", diags)
+	}
+	for i, x := range []struct {
+		line int
+		err  error
+	}{{5, ErrMalformedLine}, {7, ErrDuplicateKey}, {10, ErrMalformedLine}} {
+		if diags[i].Line != x.line || !errors.Is(diags[i], x.err) {
+			t.Errorf("diagnostic %d: expected line %d and %v, got %v", i, x.line, x.err, diags[i])
+		}
+	}
+}
+
+func TestParsePluralForms(t *testing.T) {
+	tests := []struct {
+		code    string
+		forms   string
+		byIndex []PluralCategory
+	}{
+		{"cs", "nplurals=3; plural=(n==1) ? 0 : (n>=2 && n<=4) ? 1 : 2;", []PluralCategory{PluralOne, PluralFew, PluralOther}},
+		{"ru", poPluralForms("ru"), []PluralCategory{PluralOne, PluralFew, PluralMany}},
+		{"ar", poPluralForms("ar"), []PluralCategory{PluralZero, PluralOne, PluralTwo, PluralFew, PluralMany, PluralOther}},
+		{"fr", poPluralForms("fr-CA"), []PluralCategory{PluralOne, PluralOther}},
+		{"ja", poPluralForms("ja"), []PluralCategory{PluralOther}},
+		{"", defaultPluralForms, []PluralCategory{PluralOne, PluralOther}},
+	}
+
+	for _, tt := range tests {
+		gp, err := parsePluralForms(tt.forms)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.code, err)
+		}
+		byCategory, byIndex := gp.categories(tt.code)
+		if !reflect.DeepEqual(byIndex, tt.byIndex) {
+			t.Errorf("%s: expected %v, got %v", tt.code, tt.byIndex, byIndex)
+		}
+		if _, ok := byCategory[PluralOther]; !ok {
+			t.Errorf("%s: expected index of other", tt.code)
+		}
+	}
+
+	// ru: "other" is used for fractions only, it gets the last index
+	gp, _ := parsePluralForms(poPluralForms("ru"))
+	if byCategory, _ := gp.categories("ru"); byCategory[PluralOther] != 2 {
+		t.Errorf("expected other at 2, got %d", byCategory[PluralOther])
+	}
+
+	for _, s := range []string{"", "nplurals=2;", "nplurals=x; plural=n;", "nplurals=2; plural=(n != 1;", "nplurals=2; plural=n ? 1;"} {
+		if _, err := parsePluralForms(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
+
+// buildMO returns a MO file of original and translated strings.
+func buildMO(bo binary.ByteOrder, messages map[string]string) []byte {
+	originals := make([]string, 0, len(messages))
+	for o := range messages {
+		originals = append(originals, o)
+	}
+	sort.Strings(originals)
+
+	n := uint32(len(originals))
+	header := make([]byte, 28+16*n)
+	bo.PutUint32(header, moMagic)
+	bo.PutUint32(header[8:], n)
+	bo.PutUint32(header[12:], 28)
+	bo.PutUint32(header[16:], 28+8*n)
+
+	var strs []byte
+	offset := uint32(len(header))
+	put := func(at uint32, s string) {
+		bo.PutUint32(header[at:], uint32(len(s)))
+		bo.PutUint32(header[at+4:], offset+uint32(len(strs)))
+		strs = append(strs, s...)
+		strs = append(strs, 0)
+	}
+	for i, o := range originals {
+		put(28+8*uint32(i), o)
+	}
+	for i, o := range originals {
+		put(28+8*n+8*uint32(i), messages[o])
+	}
+	return append(header, strs...)
+}
+
+func TestMOParser(t *testing.T) {
+	messages := map[string]string{
+		"":                  "Language: cs\nPlural-Forms: nplurals=3; plural=(n==1) ? 0 : (n>=2 && n<=4) ? 1 : 2;\n",
+		"Save":              "Uložit",
+		"toolbar\x04Open":   "Otevřít",
+		"# file\x00# files": "# soubor\x00# soubory\x00# souborů",
+		"Untranslated":      "",
+	}
+	expected := []Item{
+		{Key: "# file", Value: "# souborů", Variants: &Variants{
+			"one":   "# soubor",
+			"few":   "# soubory",
+			"other": "# souborů",
+		}},
+		{Key: "Save", Value: "Uložit"},
+		{Key: "Open", Context: "toolbar", Value: "Otevřít"},
+	}
+
+	var p MOParser
+	for _, bo := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		items, err := p.ParseFileContent(buildMO(bo, messages))
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", bo, err)
+		}
+		if !reflect.DeepEqual(items, expected) {
+			t.Errorf("%v: expected %+v, got %+v", bo, expected, items)
+		}
+	}
+
+	data := buildMO(binary.LittleEndian, messages)
+	for _, broken := range [][]byte{data[:10], append([]byte{0, 0, 0, 0}, data[4:]...), data[:40]} {
+		if _, err := p.ParseFileContent(broken); !errors.Is(err, ErrInvalidMO) {
+			t.Errorf("expected ErrInvalidMO, got %v", err)
+		}
+	}
+
+	// the number of strings exceeding the content is not allocated
+	huge := make([]byte, 28)
+	binary.LittleEndian.PutUint32(huge, moMagic)
+	binary.LittleEndian.PutUint32(huge[8:], 0x7fffffff)
+	binary.LittleEndian.PutUint32(huge[12:], 28)
+	binary.LittleEndian.PutUint32(huge[16:], 28)
+	if _, err := p.ParseFileContent(huge); !errors.Is(err, ErrInvalidMO) {
+		t.Errorf("expected ErrInvalidMO, got %v", err)
+	}
+}
+
+func TestEncodePO(t *testing.T) {
+	items := []Item{
+		{Key: "Save", Value: "Uložit", Hint: "save button\nsecond line"},
+		{Key: "Open", Context: "toolbar", Value: "Otevřít"},
+		{Key: "# file", Value: "# souborů", Variants: &Variants{
+			"one":   "# soubor",
+			"few":   "# soubory",
+			"many":  "# souboru",
+			"other": "# souborů",
+		}},
+		{Key: "Terms", Value: "First line.\nSecond \"line\"."},
+		{Key: "Welcome", Value: "Vítejte", Variants: &Variants{"female": "Vítejte, madam"}},
+	}
+
+	var buf bytes.Buffer
+	if err := EncodePO(&buf, items, "cs-CZ"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `msgid ""
+msgstr ""
+"Language: cs_CZ\n"
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Content-Transfer-Encoding: 8bit\n"
+"Plural-Forms: nplurals=3; plural=(n==1) ? 0 : (n>=2 && n<=4) ? 1 : 2;\n"
+
+# save button
+# second line
+msgid "Save"
+msgstr "Uložit"
+
+msgctxt "toolbar"
+msgid "Open"
+msgstr "Otevřít"
+
+msgid "# file"
+msgid_plural "# file"
+msgstr[0] "# soubor"
+msgstr[1] "# soubory"
+msgstr[2] "# souborů"
+
+msgid "Terms"
+msgstr ""
+"First line.\n"
+"Second \"line\"."
+
+msgid "Welcome"
+msgstr "Vítejte"
+`
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+
+	var p POParser
+	parsed, diags, err := p.ParseFileDiagnostics(buf.Bytes())
+	if err != nil || len(diags) != 0 {
+		t.Fatalf("unexpected error: %v, %v", err, diags)
+	}
+	if len(parsed) != len(items) || parsed[0].Hint != items[0].Hint || parsed[3].Value != items[3].Value {
+		t.Errorf("unexpected round trip %+v", parsed)
+	}
+	if v := parsed[2].variants(); len(v) != 3 || v["few"] != "# soubory" {
+		t.Errorf("unexpected variants %v", v)
+	}
+
+	if err := EncodePO(&buf, []Item{{Value: "x"}}, "cs"); !errors.Is(err, ErrEmptyKey) {
+		t.Errorf("expected ErrEmptyKey, got %v", err)
+	}
+}
+
+func TestGettextFilenameParser(t *testing.T) {
+	r := NewLanguageRegistry()
+	p := GettextFilenameParser{Registry: r, DefaultDomain: "app"}
+
+	tests := []struct {
+		fullname  string
+		name      string
+		code      string
+		namespace string
+	}{
+		{"/srv/locale/cs/LC_MESSAGES/app.po", "cs/LC_MESSAGES/app.po", "cs", ""},
+		{"locale/pt_BR/LC_MESSAGES/grid.mo", "pt_BR/LC_MESSAGES/grid.mo", "pt-BR", "grid"},
+		{"sr_RS@latin/LC_MESSAGES/app.po", "sr_RS@latin/LC_MESSAGES/app.po", "sr-Latn-RS", ""},
+		{"de_DE.UTF-8/LC_MESSAGES/app.po", "de_DE.UTF-8/LC_MESSAGES/app.po", "de-DE", ""},
+		{"/srv/po/fr.po", "fr.po", "fr", ""},
+	}
+
+	for _, tt := range tests {
+		name, err := p.ExtractFilename(tt.fullname)
+		if err != nil || name != tt.name {
+			t.Errorf("%s: expected %s, got %s, %v", tt.fullname, tt.name, name, err)
+			continue
+		}
+		li, ns := p.ParseFilename(name)
+		if code := r.Code(li); code != tt.code || ns != tt.namespace {
+			t.Errorf("%s: expected %s %q, got %s %q", name, tt.code, tt.namespace, code, ns)
+		}
+	}
+
+	if li, _ := p.ParseFilename("C/LC_MESSAGES/app.po"); li != Unknown {
+		t.Errorf("expected Unknown, got %v", li)
+	}
+}
+
+func TestContainer_Gettext(t *testing.T) {
+	r := NewLanguageRegistry()
+	en, cs := r.Parse("en"), r.Parse("cs")
+
+	tc := NewContainer(
+		WithLanguageRegistry(r),
+		WithPrimaryLanguage(en),
+		WithFilenameParser(&GettextFilenameParser{Registry: r, DefaultDomain: "app"}),
+		WithCustomFileParser(&POParser{}),
+		WithStorage(mapStorage{
+			"locale/en/LC_MESSAGES/app.po":  "msgid \"Save\"\nmsgstr \"Save\"\n",
+			"locale/cs/LC_MESSAGES/app.po":  testPO,
+			"locale/cs/LC_MESSAGES/grid.po": "msgid \"Save\"\nmsgstr \"Uložit řádek\"\n",
+		}),
+	)
+	if err := tc.ReadRegisteredFiles(); err != nil {
+		t.Fatalf("ReadRegisteredFiles failed: %v", err)
+	}
+
+	tr := tc.Lang(cs)
+	if got := tr.Value("Save"); got != "Uložit" {
+		t.Errorf("expected 'Uložit', got '%s'", got)
+	}
+	if got := tr.ValueCtx("toolbar", "Open"); got != "Otevřít" {
+		t.Errorf("expected 'Otevřít', got '%s'", got)
+	}
+	if got := tr.Plural("# file", 3); got != "3 soubory" {
+		t.Errorf("expected '3 soubory', got '%s'", got)
+	}
+	if got := tc.Namespace("grid", cs).Value("Save"); got != "Uložit řádek" {
+		t.Errorf("expected 'Uložit řádek', got '%s'", got)
+	}
+	if w := tc.Warnings(); len(w) != 0 {
+		t.Errorf("unexpected warnings %v", w)
+	}
+}
+
+func TestContainer_GettextParserRegistry(t *testing.T) {
+	r := NewLanguageRegistry()
+	tc := NewContainer(
+		WithLanguageRegistry(r),
+		WithFilenameParser(&GettextFilenameParser{DefaultDomain: "app"}),
+		WithCustomFileParser(&POParser{}),
+		WithStorage(mapStorage{"locale/qab/LC_MESSAGES/app.po": "msgid \"Save\"\nmsgstr \"Uložit\"\n"}),
+	)
+	if err := tc.ReadRegisteredFiles(); err != nil {
+		t.Fatalf("ReadRegisteredFiles failed: %v", err)
+	}
+
+	qab := r.Lookup("qab")
+	if qab == Unknown {
+		t.Fatalf("expected qab registered in the container registry")
+	}
+	if defaultRegistry.Lookup("qab") != Unknown {
+		t.Errorf("expected qab not registered in the default registry")
+	}
+	if got := tc.Lang(qab).Value("Save"); got != "Uložit" {
+		t.Errorf("expected 'Uložit', got '%s'", got)
+	}
+}
@@ -0,0 +1,178 @@
+package i18n
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// LanguageSource extracts language preferences from an HTTP request.
+type LanguageSource struct {
+	// Resolve returns a language code or a list of weighted codes in the
+	// Accept-Language format. Empty string means the source has no preference.
+	Resolve func(r *http.Request) string
+
+	// Vary holds the request header name the source depends on.
+	// It's added to the Vary response header.
+	Vary string
+}
+
+// FromQuery returns a source reading the language code from the URL query parameter.
+func FromQuery(param string) LanguageSource {
+	return LanguageSource{
+		Resolve: func(r *http.Request) string {
+			return r.URL.Query().Get(param)
+		},
+	}
+}
+
+// FromCookie returns a source reading the language code from the cookie.
+func FromCookie(name string) LanguageSource {
+	return LanguageSource{
+		Resolve: func(r *http.Request) string {
+			c, err := r.Cookie(name)
+			if err != nil {
+				return ""
+			}
+			return c.Value
+		},
+		Vary: "Cookie",
+	}
+}
+
+// FromHeader returns a source reading the Accept-Language header.
+func FromHeader() LanguageSource {
+	return LanguageSource{
+		Resolve: func(r *http.Request) string {
+			return r.Header.Get("Accept-Language")
+		},
+		Vary: "Accept-Language",
+	}
+}
+
+// FromPathPrefix returns a source reading the language code from the first
+// segment of the URL path, like /en-GB/orders. The path is not modified.
+func FromPathPrefix() LanguageSource {
+	return LanguageSource{
+		Resolve: func(r *http.Request) string {
+			p := strings.TrimPrefix(r.URL.Path, "/")
+			if pos := strings.IndexByte(p, '/'); pos != -1 {
+				p = p[:pos]
+			}
+			if _, err := CanonicalCode(p); err != nil {
+				return ""
+			}
+			return p
+		},
+	}
+}
+
+// FromUserProfile returns a source calling fn, usually reading the language
+// from the profile of the authenticated user.
+func FromUserProfile(fn func(r *http.Request) string) LanguageSource {
+	return LanguageSource{
+		Resolve: fn,
+	}
+}
+
+// middlewareConfig defines options for Middleware.
+type middlewareConfig struct {
+	sources   []LanguageSource
+	namespace string
+}
+
+type MiddlewareOption func(o *middlewareConfig)
+
+// WithLanguageSources assigns sources in the order of priority.
+// The first source giving an available language wins.
+// The default is FromHeader().
+func WithLanguageSources(sources ...LanguageSource) MiddlewareOption {
+	return func(o *middlewareConfig) {
+		o.sources = sources
+	}
+}
+
+// WithRequestNamespace assigns the namespace of the TranslationRequest.
+func WithRequestNamespace(namespace string) MiddlewareOption {
+	return func(o *middlewareConfig) {
+		o.namespace = namespace
+	}
+}
+
+// Middleware returns a net/http middleware negotiating the language of
+// the request and storing TranslationRequest in the request context.
+// Use FromContext to get it in a handler.
+//
+// The middleware sets Content-Language and Vary response headers.
+// If no source gives an available language, the default language is
+// used (see WithDefaultMatch).
+func (tc *TranslationContainer) Middleware(opts ...MiddlewareOption) func(http.Handler) http.Handler {
+
+	cfg := middlewareConfig{
+		sources: []LanguageSource{FromHeader()},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var vary []string
+	for _, src := range cfg.sources {
+		if src.Vary != "" {
+			vary = append(vary, src.Vary)
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			li := tc.resolveRequestLanguage(r, cfg.sources)
+			tr := tc.Namespace(cfg.namespace, li)
+
+			h := w.Header()
+			if li != Unknown {
+				h.Set("Content-Language", tc.Registry().Code(li))
+			}
+			for _, v := range vary {
+				h.Add("Vary", v)
+			}
+
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), tr)))
+		})
+	}
+}
+
+// resolveRequestLanguage returns the language of the first source
+// matched with confidence higher than ConfidenceNo.
+func (tc *TranslationContainer) resolveRequestLanguage(r *http.Request, sources []LanguageSource) Language {
+
+	for _, src := range sources {
+		val := src.Resolve(r)
+		if val == "" {
+			continue
+		}
+
+		if li, conf := tc.MatchConfidence(val); conf > ConfidenceNo {
+			return li
+		}
+	}
+
+	li, _ := tc.MatchConfidence("")
+	return li
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the TranslationRequest.
+func NewContext(ctx context.Context, tr TranslationRequest) context.Context {
+	return context.WithValue(ctx, contextKey{}, tr)
+}
+
+// FromContext returns the TranslationRequest stored by Middleware or NewContext.
+// Returns a TranslationRequest of Unknown language without translations
+// if ctx has none.
+func FromContext(ctx context.Context) TranslationRequest {
+	if tr, ok := ctx.Value(contextKey{}).(TranslationRequest); ok {
+		return tr
+	}
+	return TranslationRequest{lang: Unknown}
+}
@@ -0,0 +1,78 @@
+package i18n
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestContainer_Middleware(t *testing.T) {
+	r := NewLanguageRegistry()
+	en := r.Parse("en")
+	tc := newTestContainer(t, r, WithPrimaryLanguage(en))
+
+	var got TranslationRequest
+	h := tc.Middleware(
+		WithLanguageSources(
+			FromQuery("lang"),
+			FromCookie("lang"),
+			FromPathPrefix(),
+			FromHeader(),
+		),
+		WithRequestNamespace("customer1"),
+	)(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		got = FromContext(req.Context())
+	}))
+
+	tests := []struct {
+		name   string
+		url    string
+		cookie string
+		header string
+		lang   string
+	}{
+		{"query", "/x?lang=de", "en-GB", "en-US", "de"},
+		{"cookie", "/x", "en-gb", "de", "en-GB"},
+		{"path", "/en-US/orders", "", "de", "en-US"},
+		{"header", "/orders", "", "fr, de;q=0.5", "de"},
+		{"unavailable query", "/x?lang=fr", "", "de", "de"},
+		{"default", "/x", "", "", "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "lang", Value: tt.cookie})
+			}
+			if tt.header != "" {
+				req.Header.Set("Accept-Language", tt.header)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			if code := r.Code(got.Language()); code != tt.lang {
+				t.Errorf("expected %s, got %s", tt.lang, code)
+			}
+			if got.Namespace() != "customer1" {
+				t.Errorf("expected namespace customer1, got %s", got.Namespace())
+			}
+			if cl := w.Header().Get("Content-Language"); cl != tt.lang {
+				t.Errorf("expected Content-Language %s, got %s", tt.lang, cl)
+			}
+			if vary := w.Header().Values("Vary"); len(vary) != 2 || vary[0] != "Cookie" || vary[1] != "Accept-Language" {
+				t.Errorf("unexpected Vary %v", vary)
+			}
+		})
+	}
+}
+
+func TestFromContext_Empty(t *testing.T) {
+	tr := FromContext(httptest.NewRequest(http.MethodGet, "/", nil).Context())
+	if tr.Language() != Unknown {
+		t.Errorf("expected Unknown, got %d", tr.Language())
+	}
+	if got := tr.Value("Save"); got != "Save" {
+		t.Errorf("expected 'Save', got '%s'", got)
+	}
+}
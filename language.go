@@ -1,11 +1,16 @@
 package i18n
 
 import (
-	"strings"
+	"errors"
+	"fmt"
 	"sync"
+	"sync/atomic"
 )
 
 // Language is index of the language in the slice of language codes.
+//
+// Language values are meaningful only within the LanguageRegistry which
+// issued them. Package level functions use the default registry.
 type Language int
 
 // Unknown holds Language value for unknown language.
@@ -14,137 +19,431 @@ const Unknown Language = -1
 // UnknownLanguageCode is used if Language is unknown.
 var UnknownLanguageCode string = "?"
 
-var (
-	// Global variables protected by mutex.
-	mux      sync.RWMutex //
-	codes    []string     // slice of language codes
-	nextCode []Language   // index of the next language code in the hierarchy
-)
+// ErrFallbackCycle is returned by SetFallback if the declared chain
+// leads back to a language already visited.
+var ErrFallbackCycle = errors.New("fallback chain cycle")
+
+// LanguageRegistry holds a set of language codes and links between them.
+// The zero value is not usable, use NewLanguageRegistry.
+type LanguageRegistry struct {
+	mux      sync.RWMutex
+	codes    []string            // slice of language codes
+	nextCode []Language          // index of the next language code in the hierarchy
+	index    map[string]Language // language code -> index in codes
+
+	// fallbacks holds explicitly declared fallback chains.
+	fallbacks map[Language][]Language
+
+	// chains holds resolved fallback chains, starting with the language itself.
+	// Slices are never modified after assignment and can be shared.
+	chains [][]Language
+
+	// generation is incremented by every change of chains, containers
+	// compare it to refresh chains cached in their snapshots.
+	generation atomic.Uint64
+}
+
+// NewLanguageRegistry creates an empty language registry.
+func NewLanguageRegistry() *LanguageRegistry {
+	return &LanguageRegistry{
+		index:     make(map[string]Language),
+		fallbacks: make(map[Language][]Language),
+	}
+}
+
+// defaultRegistry is used by package level functions.
+var defaultRegistry = NewLanguageRegistry()
+
+// DefaultRegistry returns the registry used by package level functions.
+func DefaultRegistry() *LanguageRegistry {
+	return defaultRegistry
+}
 
 // String implements fmt.Stringer interface.
+// The code is taken from the default registry, thus languages of other
+// registries print wrong codes or UnknownLanguageCode, use
+// LanguageRegistry.Code for them.
 func (li Language) String() string {
-	return code(li)
+	return defaultRegistry.Code(li)
 }
 
-// code returns language code by language index.
-// Returns UnknownCode variable if language index is invalid.
-func code(li Language) string {
-	mux.RLock()
-	defer mux.RUnlock()
-	if int(li) >= len(codes) || li < 0 {
+// Code returns language code by language index.
+// Returns UnknownLanguageCode variable if language index is invalid.
+func (r *LanguageRegistry) Code(li Language) string {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	if int(li) >= len(r.codes) || li < 0 {
 		return UnknownLanguageCode
 	}
-	return codes[li]
+	return r.codes[li]
 }
 
 // Lookup returns Language by language code.
 // Returns Unknown if language code is not found.
 func Lookup(code string) Language {
-	mux.RLock()
-	defer mux.RUnlock()
-	return getLanguage(code)
+	return defaultRegistry.Lookup(code)
+}
+
+// Lookup returns Language by language code.
+// The code is canonicalized before lookup, so "en_us" finds "en-US".
+// Returns Unknown if language code is not found.
+func (r *LanguageRegistry) Lookup(code string) Language {
+	r.mux.RLock()
+	res := r.getLanguage(code)
+	r.mux.RUnlock()
+	if res != Unknown {
+		return res
+	}
+
+	canonical, err := CanonicalCode(code)
+	if err != nil || canonical == code {
+		return Unknown
+	}
+
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	return r.getLanguage(canonical)
 }
 
 // Parse parses language code and returns Language.
-// If language code is not found, it adds it.
+// If language code is not found, it adds it to the default registry.
+// Returns Unknown if code is not a well-formed BCP 47 language tag.
 //
 // If language code is complex, like zh-Hans-CN, than adds zh-Hans-CN, zh-Hans, zh codes
 // and returns index of the first added Language.
 func Parse(code string) Language {
+	return defaultRegistry.Parse(code)
+}
 
-	mux.RLock()
-	res := getLanguage(code)
-	mux.RUnlock()
+// ParseStrict is like Parse but returns an error if code is not
+// a well-formed BCP 47 language tag.
+func ParseStrict(code string) (Language, error) {
+	return defaultRegistry.ParseStrict(code)
+}
+
+// Parse parses language code and returns Language.
+// If language code is not found, it adds it.
+// Returns Unknown if code is not a well-formed BCP 47 language tag.
+//
+// The code is canonicalized first (see CanonicalCode), thus "EN_us",
+// "en-us" and "en-US" are the same Language.
+//
+// If language code is complex, like zh-Hans-CN, than adds zh-Hans-CN, zh-Hans, zh codes
+// and returns index of the first added Language.
+func (r *LanguageRegistry) Parse(code string) Language {
+	li, _ := r.ParseStrict(code)
+	return li
+}
+
+// ParseStrict is like Parse but returns an error if code is not
+// a well-formed BCP 47 language tag.
+func (r *LanguageRegistry) ParseStrict(code string) (Language, error) {
+
+	r.mux.RLock()
+	res := r.getLanguage(code)
+	r.mux.RUnlock()
 	if res != Unknown {
-		return res
+		return res, nil
+	}
+
+	code, err := CanonicalCode(code)
+	if err != nil {
+		return Unknown, err
 	}
 
 	// following part of the code is rearly used, only when new language is added.
-	mux.Lock()
-	defer mux.Unlock()
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if res = r.getLanguage(code); res != Unknown {
+		return res, nil
+	}
 
 	var parts []string //zh-Hans-CN -> [zh-Hans-CN, zh-Hans, zh]
 
 	for {
 		parts = append(parts, code)
-		pos := strings.LastIndex(code, "-")
-		if pos == -1 {
+		parent, ok := parentCode(code)
+		if !ok {
 			break
 		}
-		code = code[:pos]
+		code = parent
 	}
 
 	for i := len(parts) - 1; i >= 0; i-- {
 		next := Unknown
 		if i < len(parts)-1 {
-			next = getLanguage(parts[i+1])
+			next = r.getLanguage(parts[i+1])
 		}
-		li, added := getSetLanguage(parts[i])
+		li, added := r.getSetLanguage(parts[i], next)
 		if added {
-			nextCode[li] = next
 			res = li
 		}
 	}
 
-	return res
+	return res, nil
 }
 
 // getLanguage returns Language by code.
 // Returns Unknown if code is not found.
-func getLanguage(code string) Language {
-	cnt := len(codes)
-	for i := 0; i < cnt; i++ {
-		if codes[i] == code {
-			return Language(i)
-		}
+func (r *LanguageRegistry) getLanguage(code string) Language {
+	if li, ok := r.index[code]; ok {
+		return li
 	}
 	return Unknown
 }
 
 // getSetLanguage returns Language by language code.
-// If language code is not found, than adds it.
-func getSetLanguage(code string) (li Language, added bool) {
+// If language code is not found, than adds it with the next language
+// in the hierarchy.
+func (r *LanguageRegistry) getSetLanguage(code string, next Language) (li Language, added bool) {
 
-	if li := getLanguage(code); li != Unknown {
+	if li := r.getLanguage(code); li != Unknown {
 		return li, false
 	}
 
-	codes = append(codes, code)
-	nextCode = append(nextCode, Unknown)
-	return Language(len(codes) - 1), true
+	li = Language(len(r.codes))
+	r.codes = append(r.codes, code)
+	r.nextCode = append(r.nextCode, next)
+	r.index[code] = li
+
+	// a new language has no declared fallbacks, its chain is the chain of the parent.
+	chain := []Language{li}
+	if next != Unknown {
+		chain = append(chain, r.chains[next]...)
+	}
+	r.chains = append(r.chains, chain)
+	r.generation.Add(1)
+	return li, true
 }
 
 // LanguageCodes returns copy of the slice of language codes.
 func LanguageCodes() []string {
-	mux.RLock()
-	defer mux.RUnlock()
-	res := make([]string, len(codes))
-	copy(res, codes)
+	return defaultRegistry.LanguageCodes()
+}
+
+// LanguageCodes returns copy of the slice of language codes.
+func (r *LanguageRegistry) LanguageCodes() []string {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	res := make([]string, len(r.codes))
+	copy(res, r.codes)
 	return res
 }
 
 // LastLanguage returns last Language.
 // It is used to iterate over all registered languages.
 func LastLanguage() Language {
-	mux.RLock()
-	defer mux.RUnlock()
-	return Language(len(codes) - 1)
+	return defaultRegistry.LastLanguage()
+}
+
+// LastLanguage returns last Language.
+// It is used to iterate over all registered languages.
+func (r *LanguageRegistry) LastLanguage() Language {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	return Language(len(r.codes) - 1)
 }
 
 // NextLanguage returns index of the next language code in the hierarchy.
 // Returns Unknown if language index is invalid or is the last one (Unknown).
 func NextLanguage(li Language) Language {
-	mux.RLock()
-	defer mux.RUnlock()
-	if int(li) >= len(nextCode) || li < 0 {
+	return defaultRegistry.NextLanguage(li)
+}
+
+// NextLanguage returns index of the next language code in the hierarchy.
+// If fallback chain is declared for li, the first language of the chain is returned.
+// Returns Unknown if language index is invalid or is the last one (Unknown).
+//
+// Use Fallbacks to get the full chain, because the next language of
+// the next language may differ from the declared chain.
+func (r *LanguageRegistry) NextLanguage(li Language) Language {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	if int(li) >= len(r.nextCode) || li < 0 {
 		return Unknown
 	}
-	return nextCode[li]
+	if fb, ok := r.fallbacks[li]; ok {
+		return fb[0]
+	}
+	return r.nextCode[li]
+}
+
+// SetFallback declares fallback chain of the language in the default registry.
+func SetFallback(li Language, chain ...Language) error {
+	return defaultRegistry.SetFallback(li, chain...)
+}
+
+// Fallbacks returns fallback chain of the language in the default registry.
+func Fallbacks(li Language) []Language {
+	return defaultRegistry.Fallbacks(li)
+}
+
+// SetFallback declares fallback chain of the language li, replacing
+// the implicit parent (zh-Hans-CN -> zh-Hans). The last language of
+// the chain continues with its own chain.
+//
+// Example:
+// SetFallback(ptBR, ptPT, pt, en) gives pt-BR -> pt-PT -> pt -> en
+// SetFallback(srLatn, sr, hr) gives sr-Latn -> sr -> hr
+//
+// Empty chain restores the implicit parent.
+// Returns ErrFallbackCycle if the chain leads back to a visited language.
+func (r *LanguageRegistry) SetFallback(li Language, chain ...Language) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if int(li) >= len(r.codes) || li < 0 {
+		return fmt.Errorf("set fallback: unknown language %d", li)
+	}
+
+	for _, fb := range chain {
+		if int(fb) >= len(r.codes) || fb < 0 {
+			return fmt.Errorf("set fallback %s: unknown language %d", r.codes[li], fb)
+		}
+	}
+
+	prev, declared := r.fallbacks[li]
+	if len(chain) == 0 {
+		delete(r.fallbacks, li)
+	} else {
+		r.fallbacks[li] = append([]Language(nil), chain...)
+	}
+
+	chains := make([][]Language, len(r.codes))
+	for i := range r.codes {
+		c, err := r.resolveChain(Language(i))
+		if err != nil {
+			if declared {
+				r.fallbacks[li] = prev
+			} else {
+				delete(r.fallbacks, li)
+			}
+			return err
+		}
+		chains[i] = c
+	}
+	r.chains = chains
+	r.generation.Add(1)
+	return nil
+}
+
+// SetFallbackCodes is like SetFallback but accepts language codes.
+// Unknown codes are registered.
+func (r *LanguageRegistry) SetFallbackCodes(code string, chain ...string) error {
+	li, err := r.ParseStrict(code)
+	if err != nil {
+		return err
+	}
+
+	fbs := make([]Language, len(chain))
+	for i := range chain {
+		if fbs[i], err = r.ParseStrict(chain[i]); err != nil {
+			return err
+		}
+	}
+	return r.SetFallback(li, fbs...)
+}
+
+// resolveChain walks declared and implicit links starting from li.
+func (r *LanguageRegistry) resolveChain(li Language) ([]Language, error) {
+
+	res := []Language{li}
+	seen := map[Language]bool{li: true}
+	expanded := make(map[Language]bool)
+
+	for cur := li; ; {
+		if expanded[cur] {
+			return nil, fmt.Errorf("%w: %s", ErrFallbackCycle, r.codes[li])
+		}
+		expanded[cur] = true
+
+		next, ok := r.fallbacks[cur]
+		if !ok {
+			if r.nextCode[cur] == Unknown {
+				break
+			}
+			next = []Language{r.nextCode[cur]}
+		}
+
+		for _, n := range next {
+			if n == cur {
+				return nil, fmt.Errorf("%w: %s", ErrFallbackCycle, r.codes[li])
+			}
+			if !seen[n] {
+				seen[n] = true
+				res = append(res, n)
+			}
+		}
+		cur = next[len(next)-1]
+	}
+	return res, nil
+}
+
+// Fallbacks returns copy of the fallback chain of the language li,
+// not including li itself.
+func (r *LanguageRegistry) Fallbacks(li Language) []Language {
+	chain := r.chain(li)
+	if len(chain) < 2 {
+		return nil
+	}
+	res := make([]Language, len(chain)-1)
+	copy(res, chain[1:])
+	return res
+}
+
+// chain returns resolved fallback chain starting with li.
+// The result shall not be modified.
+func (r *LanguageRegistry) chain(li Language) []Language {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	if int(li) >= len(r.chains) || li < 0 {
+		return nil
+	}
+	return r.chains[li]
+}
+
+// fallbackChains returns resolved chains of all languages.
+func (r *LanguageRegistry) fallbackChains() *fallbackChains {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	return &fallbackChains{
+		generation: r.generation.Load(),
+		chains:     r.chains[:len(r.chains):len(r.chains)],
+	}
+}
+
+// fallbackChains is an immutable copy of resolved chains of a registry.
+type fallbackChains struct {
+	generation uint64
+	chains     [][]Language
+}
+
+// chain returns the fallback chain starting with li.
+func (fc *fallbackChains) chain(li Language) []Language {
+	if int(li) >= len(fc.chains) || li < 0 {
+		return nil
+	}
+	return fc.chains[li]
+}
+
+// chainCodes returns codes of the fallback chain starting with li.
+func (r *LanguageRegistry) chainCodes(li Language) []string {
+	var codes []string
+	for _, l := range r.chain(li) {
+		codes = append(codes, r.Code(l))
+	}
+	return codes
 }
 
 // LanguageCount returns number of supported languages.
 func LanguageCount() int {
-	mux.RLock()
-	defer mux.RUnlock()
-	return len(codes)
+	return defaultRegistry.LanguageCount()
+}
+
+// LanguageCount returns number of supported languages.
+func (r *LanguageRegistry) LanguageCount() int {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	return len(r.codes)
 }
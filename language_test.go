@@ -1,67 +1,75 @@
 package i18n
 
 import (
+	"errors"
 	"strings"
 	"testing"
 )
 
+// newTestRegistry returns a registry with the codes registered in the given order.
+func newTestRegistry(codes ...string) *LanguageRegistry {
+	r := NewLanguageRegistry()
+	for _, c := range codes {
+		r.getSetLanguage(c, Unknown)
+	}
+	return r
+}
+
 func TestCode(t *testing.T) {
-	resetLangState()
-	codes = []string{"en", "fr", "de"}
-	if got := code(1); got != "fr" {
+	r := newTestRegistry("en", "fr", "de")
+	if got := r.Code(1); got != "fr" {
 		t.Errorf("expected 'fr', got '%s'", got)
 	}
-	if got := code(10); got != UnknownLanguageCode {
+	if got := r.Code(10); got != UnknownLanguageCode {
 		t.Errorf("expected '%s', got '%s'", UnknownLanguageCode, got)
 	}
 
 }
 
 func TestIndex(t *testing.T) {
-	resetLangState()
-	codes = []string{"en", "fr", "de"}
-	if got := Lookup("fr"); got != 1 {
+	r := newTestRegistry("en", "fr", "de")
+	if got := r.Lookup("fr"); got != 1 {
 		t.Errorf("expected 1, got %d", got)
 	}
-	if got := Lookup("es"); got != Unknown {
+	if got := r.Lookup("es"); got != Unknown {
 		t.Errorf("expected Unknown, got %d", got)
 	}
 
-	if en := Lookup("en"); en.String() != "en" {
-		t.Errorf("expected 'en', got '%s'", en)
+	if en := r.Lookup("en"); r.Code(en) != "en" {
+		t.Errorf("expected 'en', got '%s'", r.Code(en))
 	}
 
+	if en := Parse("en"); en.String() != "en" {
+		t.Errorf("expected 'en', got '%s'", en)
+	}
 }
 
 func TestLangCodes(t *testing.T) {
-	resetLangState()
-	codes = []string{"en", "fr", "de"}
-	if got := LanguageCodes(); !equalSlices(got, []string{"en", "fr", "de"}) {
+	r := newTestRegistry("en", "fr", "de")
+	if got := r.LanguageCodes(); !equalSlices(got, []string{"en", "fr", "de"}) {
 		t.Errorf("expected ['en', 'fr', 'de'], got %v", got)
 	}
 }
 
 func TestNextLangIndex(t *testing.T) {
-	resetLangState()
-	codes = []string{"en", "fr"}
-	nextCode = []Language{1, Unknown}
-	if got := NextLanguage(0); got != 1 {
+	r := newTestRegistry("en", "fr")
+	r.nextCode = []Language{1, Unknown}
+	if got := r.NextLanguage(0); got != 1 {
 		t.Errorf("expected 1, got %d", got)
 	}
-	if got := NextLanguage(10); got != Unknown {
+	if got := r.NextLanguage(10); got != Unknown {
 		t.Errorf("expected Unknown, got %d", got)
 	}
 }
 
 func TestToName(t *testing.T) {
-	resetLangState()
-	codes = []string{"en", "fr"}
+	r := newTestRegistry("en", "fr")
 	data := []byte(`{"en":"Hello","fr":"Bonjour"}`)
-	n, err := ToString(data)
+	n, err := r.ToString(data)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if got := n.InLang(1); got != "Bonjour" {
+	if got := n.InLangIn(r, 1); got != "Bonjour" {
 		t.Errorf("expected 'Bonjour', got '%s'", got)
 	}
 }
@@ -91,35 +99,47 @@ func TestGetSetLangIndex(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		resetLangState()
 		t.Run(strings.Join(tt.input, ","), func(t *testing.T) {
+			r := NewLanguageRegistry()
 			for _, code := range tt.input {
-				_ = Parse(code)
+				_ = r.Parse(code)
 			}
-			if gotCodes := LanguageCodes(); !equalSlices(gotCodes, tt.expectedCodes) {
+			if gotCodes := r.LanguageCodes(); !equalSlices(gotCodes, tt.expectedCodes) {
 				t.Errorf("expected codes %v, got %v", tt.expectedCodes, gotCodes)
 			}
-			if gotNext := getNextCodesSnapshot(); !equalLanguageIndices(gotNext, tt.expectedNext) {
+			if gotNext := getNextCodesSnapshot(r); !equalLanguageIndices(gotNext, tt.expectedNext) {
 				t.Errorf("expected nextCode %v, got %v", tt.expectedNext, gotNext)
 			}
 		})
 	}
 }
 
-// resetLangState resets the global state of the language package.
-func resetLangState() {
-	mux.Lock()
-	defer mux.Unlock()
-	codes = nil
-	nextCode = nil
-	NoFoundIndex = Unknown
+func TestRegistryIsolation(t *testing.T) {
+	a := NewLanguageRegistry()
+	b := NewLanguageRegistry()
+
+	a.Parse("en-US")
+	b.Parse("de")
+
+	if got := a.Lookup("de"); got != Unknown {
+		t.Errorf("expected Unknown, got %d", got)
+	}
+	if got := b.Lookup("en-US"); got != Unknown {
+		t.Errorf("expected Unknown, got %d", got)
+	}
+	if got := b.Code(b.Lookup("de")); got != "de" {
+		t.Errorf("expected 'de', got '%s'", got)
+	}
+	if got := a.LanguageCount(); got != 2 {
+		t.Errorf("expected 2, got %d", got)
+	}
 }
 
-func getNextCodesSnapshot() []Language {
-	mux.RLock()
-	defer mux.RUnlock()
-	res := make([]Language, len(nextCode))
-	copy(res, nextCode)
+func getNextCodesSnapshot(r *LanguageRegistry) []Language {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	res := make([]Language, len(r.nextCode))
+	copy(res, r.nextCode)
 	return res
 }
 
@@ -148,3 +168,80 @@ func equalLanguageIndices(a, b []Language) bool {
 	}
 	return true
 }
+
+func TestSetFallback(t *testing.T) {
+	r := NewLanguageRegistry()
+
+	ptBR := r.Parse("pt-BR")
+	ptPT := r.Parse("pt-PT")
+	pt := r.Lookup("pt")
+	en := r.Parse("en")
+
+	if err := r.SetFallback(ptBR, ptPT, pt, en); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := r.Fallbacks(ptBR); !equalLanguageIndices(got, []Language{ptPT, pt, en}) {
+		t.Errorf("expected [pt-PT pt en], got %v", got)
+	}
+	if got := r.NextLanguage(ptBR); got != ptPT {
+		t.Errorf("expected pt-PT, got %s", r.Code(got))
+	}
+
+	// pt-PT keeps the implicit parent.
+	if got := r.Fallbacks(ptPT); !equalLanguageIndices(got, []Language{pt}) {
+		t.Errorf("expected [pt], got %v", got)
+	}
+
+	// the last language of the chain continues with its own chain.
+	if err := r.SetFallbackCodes("sr-Latn", "hr"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	srLatnRS := r.Parse("sr-Latn-RS")
+	expected := []Language{r.Lookup("sr-Latn"), r.Lookup("hr")}
+	if got := r.Fallbacks(srLatnRS); !equalLanguageIndices(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	// empty chain restores the implicit parent.
+	if err := r.SetFallback(ptBR); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := r.Fallbacks(ptBR); !equalLanguageIndices(got, []Language{pt}) {
+		t.Errorf("expected [pt], got %v", got)
+	}
+}
+
+func TestSetFallback_Cycle(t *testing.T) {
+	r := NewLanguageRegistry()
+
+	sr := r.Parse("sr")
+	hr := r.Parse("hr")
+	bs := r.Parse("bs")
+
+	if err := r.SetFallback(sr, sr); !errors.Is(err, ErrFallbackCycle) {
+		t.Errorf("expected ErrFallbackCycle, got %v", err)
+	}
+
+	if err := r.SetFallback(sr, hr); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := r.SetFallback(hr, bs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := r.SetFallback(bs, sr); !errors.Is(err, ErrFallbackCycle) {
+		t.Errorf("expected ErrFallbackCycle, got %v", err)
+	}
+
+	// failed declaration does not change chains.
+	if got := r.Fallbacks(bs); len(got) != 0 {
+		t.Errorf("expected no fallbacks, got %v", got)
+	}
+	if got := r.Fallbacks(sr); !equalLanguageIndices(got, []Language{hr, bs}) {
+		t.Errorf("expected [hr bs], got %v", got)
+	}
+
+	if err := r.SetFallback(sr, 100); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
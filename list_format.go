@@ -0,0 +1,92 @@
+package i18n
+
+import "strings"
+
+// ListStyle is a kind of CLDR list pattern.
+type ListStyle int8
+
+const (
+	// ListAnd joins all items: "A, B, and C".
+	ListAnd ListStyle = iota
+	// ListOr joins alternatives: "A, B, or C".
+	ListOr
+	// ListUnit joins measurements: "5 ft, 3 in".
+	ListUnit
+)
+
+// ListFormat joins lists of items by CLDR list patterns of a language.
+type ListFormat struct {
+	patterns map[ListStyle]listPatterns
+}
+
+// Lists returns the list format of the language li of the default registry.
+func Lists(li Language) ListFormat {
+	return defaultRegistry.Lists(li)
+}
+
+// Lists returns the list format of the language li. CLDR data is taken
+// following the fallback chain of li, like translations: if de-AT falls
+// back to de and en, patterns of de-AT, de and en are used in this order,
+// each style from the first language having it.
+//
+// Built-in data covers cs, de, en, en-GB, en-IN, es, fr, it, ja, nl, pl,
+// pt, ru, uk and zh. Other locales use the data of their parent and
+// fallback languages, and English if none of them is covered.
+func (r *LanguageRegistry) Lists(li Language) ListFormat {
+	return ListFormat{patterns: resolveListData(r.chainCodes(li))}
+}
+
+// Lists returns the list format of the requested language.
+func (tr TranslationRequest) Lists() ListFormat {
+	if tr.tc == nil {
+		return defaultRegistry.Lists(tr.lang)
+	}
+	return tr.tc.Registry().Lists(tr.lang)
+}
+
+// Join joins items by the style: "A, B, and C" in English with ListAnd,
+// "A, B und C" in German.
+func (lf ListFormat) Join(items []string, style ListStyle) string {
+	p, ok := lf.patterns[style]
+	if !ok {
+		p = lf.patterns[ListAnd]
+	}
+
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	case 2:
+		return joinPair(p.two, items[0], items[1])
+	}
+
+	// lists are joined from the end: start(A, middle(B, end(C, D)))
+	last := len(items) - 1
+	res := joinPair(p.end, items[last-1], items[last])
+	for i := last - 2; i > 0; i-- {
+		res = joinPair(p.middle, items[i], res)
+	}
+	return joinPair(p.start, items[0], res)
+}
+
+func joinPair(pattern, first, second string) string {
+	return strings.NewReplacer("{0}", first, "{1}", second).Replace(pattern)
+}
+
+// JoinInLang joins values of names in the language li of the default
+// registry by the style, see LanguageRegistry.JoinInLang.
+func JoinInLang(names []String, li Language, style ListStyle, opts ...StringOption) string {
+	return defaultRegistry.JoinInLang(names, li, style, opts...)
+}
+
+// JoinInLang joins values of names in the language li by the style:
+// "Prague, Vienna and Berlin". Names are resolved by String.InLangIn
+// with opts.
+func (r *LanguageRegistry) JoinInLang(names []String, li Language, style ListStyle, opts ...StringOption) string {
+	items := make([]string, len(names))
+	for i, n := range names {
+		items[i] = n.InLangIn(r, li, opts...)
+	}
+	return r.Lists(li).Join(items, style)
+}
@@ -0,0 +1,60 @@
+package i18n
+
+import "testing"
+
+func TestListFormat_Join(t *testing.T) {
+	r := NewLanguageRegistry()
+	items := []string{"A", "B", "C", "D"}
+
+	tests := []struct {
+		code     string
+		items    []string
+		style    ListStyle
+		expected string
+	}{
+		{"en", nil, ListAnd, ""},
+		{"en", items[:1], ListAnd, "A"},
+		{"en", items[:2], ListAnd, "A and B"},
+		{"en", items[:3], ListAnd, "A, B, and C"},
+		{"en", items, ListAnd, "A, B, C, and D"},
+		{"en", items[:3], ListOr, "A, B, or C"},
+		{"en", items[:3], ListUnit, "A, B, C"},
+		{"en-GB", items[:3], ListAnd, "A, B and C"},
+		{"en-GB", items[:3], ListUnit, "A, B, C"},
+		{"de", items[:3], ListAnd, "A, B und C"},
+		{"de-AT", items[:3], ListOr, "A, B oder C"},
+		{"cs", items, ListAnd, "A, B, C a D"},
+		{"ru", items[:2], ListUnit, "A B"},
+		{"ja", items[:3], ListAnd, "A、B、C"},
+		{"xx", items[:3], ListAnd, "A, B, and C"},
+	}
+
+	for _, tt := range tests {
+		if got := r.Lists(r.Parse(tt.code)).Join(tt.items, tt.style); got != tt.expected {
+			t.Errorf("%s %v: expected %q, got %q", tt.code, tt.items, tt.expected, got)
+		}
+	}
+}
+
+func TestJoinInLang(t *testing.T) {
+	r := newTestRegistry("en", "cs")
+	var names []String
+	for _, data := range []string{
+		`{"en":"Prague","cs":"Praha"}`,
+		`{"en":"Vienna","cs":"Vídeň"}`,
+		`{"en":"Berlin","cs":"Berlín"}`,
+	} {
+		n, err := r.ToString([]byte(data))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		names = append(names, n)
+	}
+
+	if got := r.JoinInLang(names, 1, ListAnd); got != "Praha, Vídeň a Berlín" {
+		t.Errorf("expected 'Praha, Vídeň a Berlín', got %q", got)
+	}
+	if got := r.JoinInLang(names, 0, ListOr); got != "Prague, Vienna, or Berlin" {
+		t.Errorf("expected 'Prague, Vienna, or Berlin', got %q", got)
+	}
+}
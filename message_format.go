@@ -0,0 +1,590 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidMessage is returned if a value is not a valid ICU MessageFormat pattern.
+var ErrInvalidMessage = errors.New("invalid message")
+
+type msgPartKind int8
+
+const (
+	msgText msgPartKind = iota
+	msgPound
+	msgArg
+	msgPlural
+	msgSelectOrdinal
+	msgSelect
+)
+
+// msgPart is a literal text, "#" in a plural case, or an argument.
+type msgPart struct {
+	kind msgPartKind
+	text string // literal text or argument name
+
+	// typ and style hold the type and the style of a simple argument
+	// like {n, number, integer}.
+	typ   string
+	style string
+
+	offset float64
+	cases  []msgCase
+}
+
+// msgCase is a case of plural, selectordinal or select argument.
+// key is a keyword like "one" or an explicit value like "=0".
+type msgCase struct {
+	key string
+	msg message
+}
+
+// message is a compiled ICU MessageFormat pattern.
+type message []msgPart
+
+// msgParser is a recursive descent parser of ICU MessageFormat patterns.
+type msgParser struct {
+	src string
+	pos int
+}
+
+// compileMessage parses the ICU MessageFormat pattern like
+// "{count, plural, one {# item} other {# items}}".
+func compileMessage(src string) (message, error) {
+	p := msgParser{src: src}
+	m, err := p.parseMessage(0, false)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.src) {
+		return nil, p.errorf("unexpected '}'")
+	}
+	return m, nil
+}
+
+func (p *msgParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: %s at %d", ErrInvalidMessage, fmt.Sprintf(format, args...), p.pos)
+}
+
+// parseMessage parses text and arguments up to the closing '}' of the
+// nested message or the end of the pattern.
+func (p *msgParser) parseMessage(depth int, inPlural bool) (message, error) {
+	var (
+		res  message
+		text strings.Builder
+	)
+
+	flush := func() {
+		if text.Len() > 0 {
+			res = append(res, msgPart{kind: msgText, text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '\'':
+			p.parseApostrophe(&text, inPlural)
+		case c == '{':
+			flush()
+			part, err := p.parseArgument(depth, inPlural)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, part)
+		case c == '}':
+			if depth == 0 {
+				return nil, p.errorf("unexpected '}'")
+			}
+			flush()
+			return res, nil
+		case c == '#' && inPlural:
+			flush()
+			res = append(res, msgPart{kind: msgPound})
+			p.pos++
+		default:
+			text.WriteByte(c)
+			p.pos++
+		}
+	}
+
+	if depth > 0 {
+		return nil, p.errorf("unclosed '{'")
+	}
+	flush()
+	return res, nil
+}
+
+// parseApostrophe handles ICU quoting: a doubled apostrophe is a single one,
+// an apostrophe before a syntax character starts quoted text up to the
+// next single apostrophe, other apostrophes are literal.
+func (p *msgParser) parseApostrophe(text *strings.Builder, inPlural bool) {
+	p.pos++
+	if p.pos < len(p.src) && p.src[p.pos] == '\'' {
+		text.WriteByte('\'')
+		p.pos++
+		return
+	}
+	if p.pos == len(p.src) || !strings.ContainsRune("{}|", rune(p.src[p.pos])) && !(inPlural && p.src[p.pos] == '#') {
+		text.WriteByte('\'')
+		return
+	}
+
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		p.pos++
+		if c != '\'' {
+			text.WriteByte(c)
+			continue
+		}
+		if p.pos < len(p.src) && p.src[p.pos] == '\'' {
+			text.WriteByte('\'')
+			p.pos++
+			continue
+		}
+		return
+	}
+}
+
+// parseArgument parses {name}, {name, type}, {name, type, style} and
+// {name, plural|selectordinal|select, cases}. inPlural reports whether
+// the argument is nested in a plural case, where "#" is the plural value.
+func (p *msgParser) parseArgument(depth int, inPlural bool) (msgPart, error) {
+	var res msgPart
+
+	p.pos++ // {
+	p.skipSpace()
+	res.text = p.parseIdent()
+	if res.text == "" {
+		return res, p.errorf("missing argument name")
+	}
+	p.skipSpace()
+
+	if p.consume('}') {
+		res.kind = msgArg
+		return res, nil
+	}
+	if !p.consume(',') {
+		return res, p.errorf("expected ',' or '}'")
+	}
+
+	p.skipSpace()
+	typ := p.parseIdent()
+	p.skipSpace()
+
+	switch typ {
+	case "plural", "selectordinal", "select":
+		res.kind = map[string]msgPartKind{
+			"plural":        msgPlural,
+			"selectordinal": msgSelectOrdinal,
+			"select":        msgSelect,
+		}[typ]
+		if !p.consume(',') {
+			return res, p.errorf("expected ',' after %s", typ)
+		}
+		if err := p.parseCases(&res, depth, inPlural); err != nil {
+			return res, err
+		}
+		return res, nil
+	case "number", "date", "time", "spellout", "ordinal", "duration":
+	case "":
+		return res, p.errorf("missing argument type")
+	default:
+		return res, p.errorf("unsupported argument type %q", typ)
+	}
+
+	res.kind = msgArg
+	res.typ = typ
+	if p.consume('}') {
+		return res, nil
+	}
+	if !p.consume(',') {
+		return res, p.errorf("expected ',' or '}'")
+	}
+
+	// style is a raw text up to the matching '}'
+	start, level := p.pos, 0
+	for ; p.pos < len(p.src); p.pos++ {
+		switch p.src[p.pos] {
+		case '{':
+			level++
+		case '}':
+			if level == 0 {
+				res.style = strings.TrimSpace(p.src[start:p.pos])
+				p.pos++
+				return res, nil
+			}
+			level--
+		}
+	}
+	return res, p.errorf("unclosed argument %q", res.text)
+}
+
+// parseCases parses "[offset:n] key {message} key {message}...}".
+// Select cases nested in a plural case keep "#" of the plural.
+func (p *msgParser) parseCases(res *msgPart, depth int, inPlural bool) error {
+	p.skipSpace()
+	if res.kind == msgPlural && strings.HasPrefix(p.src[p.pos:], "offset:") {
+		p.pos += len("offset:")
+		p.skipSpace()
+		start := p.pos
+		for p.pos < len(p.src) && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+			p.pos++
+		}
+		off, err := strconv.Atoi(p.src[start:p.pos])
+		if err != nil {
+			return p.errorf("invalid offset")
+		}
+		res.offset = float64(off)
+	}
+
+	hasOther := false
+	for {
+		p.skipSpace()
+		if p.pos == len(p.src) {
+			return p.errorf("unclosed argument %q", res.text)
+		}
+		if p.consume('}') {
+			break
+		}
+
+		key := p.parseSelector()
+		if key == "" {
+			return p.errorf("missing selector")
+		}
+		if err := p.checkSelector(res.kind, key); err != nil {
+			return err
+		}
+		for _, c := range res.cases {
+			if c.key == key {
+				return p.errorf("duplicate selector %q", key)
+			}
+		}
+
+		p.skipSpace()
+		if !p.consume('{') {
+			return p.errorf("expected '{' after selector %q", key)
+		}
+		m, err := p.parseMessage(depth+1, inPlural || res.kind != msgSelect)
+		if err != nil {
+			return err
+		}
+		p.pos++ // }
+
+		res.cases = append(res.cases, msgCase{key: key, msg: m})
+		hasOther = hasOther || key == "other"
+	}
+
+	if !hasOther {
+		return p.errorf("argument %q has no 'other' case", res.text)
+	}
+	return nil
+}
+
+func (p *msgParser) checkSelector(kind msgPartKind, key string) error {
+	if kind == msgSelect {
+		return nil
+	}
+	if key[0] == '=' {
+		if _, err := strconv.ParseFloat(key[1:], 64); err != nil {
+			return p.errorf("invalid explicit value %q", key)
+		}
+		return nil
+	}
+	if _, ok := ParsePluralCategory(key); !ok {
+		return p.errorf("invalid plural category %q", key)
+	}
+	return nil
+}
+
+func (p *msgParser) parseSelector() string {
+	start := p.pos
+	if p.pos < len(p.src) && p.src[p.pos] == '=' {
+		p.pos++
+	}
+	return p.src[start:p.pos] + p.parseIdent()
+}
+
+// parseIdent parses an argument name, a type or a keyword.
+func (p *msgParser) parseIdent() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c <= ' ' || strings.IndexByte("{}#,'=", c) != -1 {
+			break
+		}
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *msgParser) skipSpace() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t' || p.src[p.pos] == '\n' || p.src[p.pos] == '\r') {
+		p.pos++
+	}
+}
+
+func (p *msgParser) consume(c byte) bool {
+	if p.pos < len(p.src) && p.src[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// msgEnv holds the state of message formatting.
+type msgEnv struct {
+	code    string // language code used by plural rules
+	numbers NumberFormat
+	dates   DateFormat
+	args    map[string]any
+	errs    []error
+}
+
+// format formats m. pound is the value of "#" in a plural case.
+func (env *msgEnv) format(sb *strings.Builder, m message, pound any) {
+	for i := range m {
+		part := &m[i]
+		switch part.kind {
+		case msgText:
+			sb.WriteString(part.text)
+		case msgPound:
+			if pound == nil {
+				sb.WriteByte('#')
+				continue
+			}
+			sb.WriteString(env.numbers.Decimal(pound))
+		case msgArg:
+			v, ok := env.args[part.text]
+			if !ok {
+				env.errs = append(env.errs, fmt.Errorf("%w: %s", ErrMissingArgument, part.text))
+				sb.WriteString("{" + part.text + "}")
+				continue
+			}
+			sb.WriteString(env.formatArg(part, v))
+		case msgPlural, msgSelectOrdinal:
+			env.formatPlural(sb, part)
+		case msgSelect:
+			v, ok := env.args[part.text]
+			if !ok {
+				env.errs = append(env.errs, fmt.Errorf("%w: %s", ErrMissingArgument, part.text))
+			}
+			sel := ""
+			if ok {
+				sel = fmt.Sprint(v)
+			}
+			env.format(sb, part.selectCase(sel), pound)
+		}
+	}
+}
+
+func (env *msgEnv) formatPlural(sb *strings.Builder, part *msgPart) {
+	v, ok := env.args[part.text]
+	if !ok {
+		env.errs = append(env.errs, fmt.Errorf("%w: %s", ErrMissingArgument, part.text))
+		env.format(sb, part.selectCase("other"), nil)
+		return
+	}
+
+	n, ok := toFloat(v)
+	if !ok {
+		env.errs = append(env.errs, fmt.Errorf("argument %s: %T is not a number", part.text, v))
+		env.format(sb, part.selectCase("other"), v)
+		return
+	}
+
+	// explicit values are compared with the value before offset
+	for _, c := range part.cases {
+		if c.key[0] == '=' {
+			if x, _ := strconv.ParseFloat(c.key[1:], 64); x == n {
+				env.format(sb, c.msg, env.pound(v, n, part.offset))
+				return
+			}
+		}
+	}
+
+	set := cardinalRuleSet()
+	if part.kind == msgSelectOrdinal {
+		set = ordinalRuleSet()
+	}
+
+	pound := env.pound(v, n, part.offset)
+	cat := pluralCategory(set, env.code, pound)
+	env.format(sb, part.selectCase(cat.String()), pound)
+}
+
+// pound returns the value of "#": v with the offset subtracted.
+// v is kept as is without offset, so "1.50" keeps visible fraction digits.
+func (env *msgEnv) pound(v any, n, offset float64) any {
+	if offset == 0 {
+		return v
+	}
+	return strconv.FormatFloat(n-offset, 'f', -1, 64)
+}
+
+// selectCase returns the message of the case key or the "other" case.
+func (part *msgPart) selectCase(key string) message {
+	var other message
+	for _, c := range part.cases {
+		if c.key == key {
+			return c.msg
+		}
+		if c.key == "other" {
+			other = c.msg
+		}
+	}
+	return other
+}
+
+// formatArg formats a simple argument.
+func (env *msgEnv) formatArg(part *msgPart, v any) string {
+	switch part.typ {
+	case "number":
+		switch style := part.style; {
+		case style == "integer":
+			return env.numbers.DecimalFixed(v, 0)
+		case style == "percent":
+			return env.numbers.Percent(v)
+		case style == "::compact-short":
+			return env.numbers.Compact(v)
+		case strings.HasPrefix(style, "::currency/"):
+			return env.numbers.Currency(v, strings.TrimPrefix(style, "::currency/"))
+		}
+		return env.numbers.Decimal(v)
+	case "date", "time":
+		t, ok := v.(time.Time)
+		if !ok {
+			return fmt.Sprint(v)
+		}
+		return env.formatTime(part, t)
+	}
+	return fmt.Sprint(v)
+}
+
+var messageStyles = map[string]Style{
+	"full":   StyleFull,
+	"long":   StyleLong,
+	"medium": StyleMedium,
+	"short":  StyleShort,
+	"":       StyleMedium,
+}
+
+// formatTime formats a date or time argument. The style is a name like
+// "short", a skeleton like "::yMMMd", or a CLDR pattern like "d. M. y".
+func (env *msgEnv) formatTime(part *msgPart, t time.Time) string {
+	if style, ok := messageStyles[part.style]; ok {
+		if part.typ == "date" {
+			return env.dates.Date(t, style)
+		}
+		return env.dates.Time(t, style)
+	}
+
+	if sk, ok := strings.CutPrefix(part.style, "::"); ok {
+		if res, err := env.dates.Skeleton(t, sk); err == nil {
+			return res
+		}
+		env.errs = append(env.errs, fmt.Errorf("argument %s: %w: %q", part.text, ErrUnsupportedSkeleton, sk))
+		return env.dates.Date(t, StyleMedium)
+	}
+	return env.dates.Pattern(t, part.style)
+}
+
+// toFloat converts numbers and decimal strings to float64.
+func toFloat(v any) (float64, bool) {
+	switch x := v.(type) {
+	case int:
+		return float64(x), true
+	case int8:
+		return float64(x), true
+	case int16:
+		return float64(x), true
+	case int32:
+		return float64(x), true
+	case int64:
+		return float64(x), true
+	case uint:
+		return float64(x), true
+	case uint8:
+		return float64(x), true
+	case uint16:
+		return float64(x), true
+	case uint32:
+		return float64(x), true
+	case uint64:
+		return float64(x), true
+	case float32:
+		return float64(x), true
+	case float64:
+		return x, true
+	case string:
+		f, err := strconv.ParseFloat(x, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// formatMessage formats m in the language li.
+func formatMessage(m message, r *LanguageRegistry, li Language, args map[string]any) (string, error) {
+	code := r.Code(li)
+	env := msgEnv{code: code, args: args, numbers: newNumberFormat(code), dates: r.Dates(li)}
+
+	var sb strings.Builder
+	env.format(&sb, m, nil)
+	return sb.String(), errors.Join(env.errs...)
+}
+
+// Message returns the value of the key formatted as ICU MessageFormat
+// pattern, like "{count, plural, one {# item} other {# items}}".
+//
+// Supported arguments are simple {name}, number (integer, percent,
+// ::compact-short, ::currency/EUR), date and time (short, medium, long,
+// full, ::skeleton, pattern), plural with offset and explicit values, selectordinal and
+// select. Plural categories and numbers are formatted by rules of the
+// language the item is found in.
+//
+// Message never panics. Missing arguments are reported by an error
+// wrapping ErrMissingArgument, invalid patterns by ErrInvalidMessage;
+// the value is returned as is in the latter case.
+// Use WithMessageFormat to validate patterns by ReadRegisteredFiles.
+func (tr TranslationRequest) Message(key string, args map[string]any) (string, error) {
+	if tr.tc == nil {
+		return key, nil
+	}
+
+	snap := tr.tc.snapshot()
+	item, li, ok := tr.lookupIn(snap, key, nil)
+	tr.report(key, li, ok)
+	if !ok {
+		return tr.notFound(key).Value, nil
+	}
+
+	var m message
+	if v, ok := snap.messages.Load(item); ok {
+		m = v.(message)
+	} else {
+		var err error
+		if m, err = compileMessage(item.Value); err != nil {
+			return item.Value, fmt.Errorf("key %q: %w", key, err)
+		}
+		snap.messages.Store(item, m)
+	}
+
+	res, err := formatMessage(m, tr.tc.Registry(), li, args)
+	if err != nil {
+		return res, fmt.Errorf("key %q: %w", key, err)
+	}
+	return res, nil
+}
+
+// MessageArgs is like Message, but the arguments are given as name-value
+// pairs: MessageArgs("Items", "count", 3).
+func (tr TranslationRequest) MessageArgs(key string, kv ...any) (string, error) {
+	args, argErr := pairsToArgs(kv)
+	res, err := tr.Message(key, args)
+	return res, errors.Join(err, argErr)
+}
@@ -0,0 +1,123 @@
+package i18n
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCompileMessage(t *testing.T) {
+
+	tests := []struct {
+		src      string
+		code     string
+		args     map[string]any
+		expected string
+	}{
+		{"Hello, {name}!", "en", map[string]any{"name": "Bob"}, "Hello, Bob!"},
+		{"{count, plural, one {# item} other {# items}}", "en", map[string]any{"count": 1}, "1 item"},
+		{"{count, plural, one {# item} other {# items}}", "en", map[string]any{"count": 5}, "5 items"},
+		{"{count, plural, =0 {no items} one {# item} other {# items}}", "en", map[string]any{"count": 0}, "no items"},
+		{"{count, plural, one {# položka} few {# položky} other {# položek}}", "cs", map[string]any{"count": 3}, "3 položky"},
+		{"{n, plural, offset:1 =0 {nobody} =1 {{who}} one {{who} and # other} other {{who} and # others}}", "en",
+			map[string]any{"n": 2, "who": "Ann"}, "Ann and 1 other"},
+		{"{n, plural, offset:1 =0 {nobody} =1 {{who}} one {{who} and # other} other {{who} and # others}}", "en",
+			map[string]any{"n": 1, "who": "Ann"}, "Ann"},
+		{"{place, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}", "en", map[string]any{"place": 22}, "22nd"},
+		{"{gender, select, female {She} male {He} other {They}} replied", "en", map[string]any{"gender": "female"}, "She replied"},
+		{"{gender, select, female {She} male {He} other {They}} replied", "en", map[string]any{"gender": "x"}, "They replied"},
+		{"{g, select, female {{n, plural, one {her # cat} other {her # cats}}} other {{n, plural, one {their # cat} other {their # cats}}}}",
+			"en", map[string]any{"g": "female", "n": 2}, "her 2 cats"},
+		{"{n, plural, other {{g, select, other {# items}}}}", "en", map[string]any{"n": 1234, "g": "x"}, "1,234 items"},
+		{"{n, plural, offset:1 other {{g, select, female {she and # others} other {# more}}}}", "en",
+			map[string]any{"n": 3, "g": "female"}, "she and 2 others"},
+		{"{g, select, other {# items}}", "en", map[string]any{"g": "x"}, "# items"},
+		{"It''s '{literal}' and '#' {n, plural, other {'#' is #}}", "en", map[string]any{"n": 3}, "It's {literal} and '#' # is 3"},
+		{"Don't {n, number, integer}", "en", map[string]any{"n": 2.6}, "Don't 3"},
+		{"{r, number, percent}", "en", map[string]any{"r": 0.25}, "25%"},
+		{"{d, date, short}", "en", map[string]any{"d": time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)}, "3/5/24"},
+		{"{d, date, long}", "en", map[string]any{"d": time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)}, "March 5, 2024"},
+		{"{d, date, long}", "cs", map[string]any{"d": time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)}, "5. března 2024"},
+		{"{d, date, ::yMMMM}", "cs", map[string]any{"d": time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)}, "březen 2024"},
+		{"{d, time, short}", "de", map[string]any{"d": time.Date(2024, 3, 5, 14, 7, 0, 0, time.UTC)}, "14:07"},
+	}
+
+	r := NewLanguageRegistry()
+	for _, tt := range tests {
+		m, err := compileMessage(tt.src)
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", tt.src, err)
+			continue
+		}
+		got, err := formatMessage(m, r, r.Parse(tt.code), tt.args)
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", tt.src, err)
+		}
+		if got != tt.expected {
+			t.Errorf("%q: expected '%s', got '%s'", tt.src, tt.expected, got)
+		}
+	}
+}
+
+func TestCompileMessage_Invalid(t *testing.T) {
+
+	tests := []string{
+		"Hello, {name",
+		"Hello, }",
+		"{}",
+		"{n, plural, one {# item}}",
+		"{n, plural, single {x} other {y}}",
+		"{n, plural, =x {x} other {y}}",
+		"{n, plural, one {x} one {y} other {z}}",
+		"{n, plural one {x} other {y}}",
+		"{n, unknown}",
+		"{n, select, other {x}",
+	}
+
+	for _, src := range tests {
+		if _, err := compileMessage(src); !errors.Is(err, ErrInvalidMessage) {
+			t.Errorf("%q: expected ErrInvalidMessage, got %v", src, err)
+		}
+	}
+}
+
+func TestTranslationRequest_Message(t *testing.T) {
+	r := NewLanguageRegistry()
+	en := r.Parse("en")
+	cs := r.Parse("cs")
+	csCZ := r.Parse("cs-CZ")
+
+	storage := mapStorage{
+		"en.t18n": "Items={count, plural, one {# item} other {# items}}\n",
+		"cs.t18n": "Items={count, plural, one {# položka} few {# položky} other {# položek}}\n",
+	}
+	tc := NewContainer(
+		WithLanguageRegistry(r),
+		WithPrimaryLanguage(en),
+		WithStorage(storage),
+		WithMessageFormat(),
+	)
+	if err := tc.ReadRegisteredFiles(); err != nil {
+		t.Fatalf("ReadRegisteredFiles failed: %v", err)
+	}
+
+	got, err := tc.Lang(csCZ).MessageArgs("Items", "count", 4)
+	if err != nil || got != "4 položky" {
+		t.Errorf("expected '4 položky', got '%s', %v", got, err)
+	}
+
+	got, err = tc.Lang(en).Message("Items", nil)
+	if got != "# items" || !errors.Is(err, ErrMissingArgument) {
+		t.Errorf("expected '# items' and ErrMissingArgument, got '%s', %v", got, err)
+	}
+
+	got, err = tc.Lang(cs).Message("Unknown", nil)
+	if got != "Unknown" || err != nil {
+		t.Errorf("expected 'Unknown', got '%s', %v", got, err)
+	}
+
+	storage["cs.t18n"] = "Items={count, plural, one {# položka}\n"
+	if err := tc.ReadRegisteredFiles(); !errors.Is(err, ErrInvalidMessage) {
+		t.Errorf("expected ErrInvalidMessage, got %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+package i18n
+
+import (
+	"bufio"
+	"expvar"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// LookupStats holds lookup counters of a language in a namespace.
+// Every lookup call, like Value or Plural, is counted once by the value
+// it returns.
+type LookupStats struct {
+	Language  Language
+	Namespace string
+
+	// Hits counts keys found in the requested language.
+	Hits uint64
+
+	// Fallbacks counts keys found in another language of the fallback
+	// chain or in the primary language.
+	Fallbacks uint64
+
+	// Misses counts keys not found at all, the strategy value like
+	// NotFoundMarker is returned for them.
+	Misses uint64
+}
+
+// lookupCounters are counters of a language in a namespace.
+type lookupCounters struct {
+	hits, fallbacks, misses atomic.Uint64
+}
+
+// lookupMetrics counts lookups. Counters of known languages and namespaces
+// are found without locking, see sync.Map.
+type lookupMetrics struct {
+	counters sync.Map // key -> *lookupCounters
+}
+
+// record counts the lookup in the language li and the namespace.
+func (m *lookupMetrics) record(li Language, namespace string, resolved Language, found bool) {
+	k := key{lang: li, namespace: namespace}
+	v, ok := m.counters.Load(k)
+	if !ok {
+		v, _ = m.counters.LoadOrStore(k, &lookupCounters{})
+	}
+
+	c := v.(*lookupCounters)
+	switch {
+	case !found:
+		c.misses.Add(1)
+	case resolved != li:
+		c.fallbacks.Add(1)
+	default:
+		c.hits.Add(1)
+	}
+}
+
+// WithMetrics enables counting of lookups by requested language and
+// namespace, see TranslationContainer.Stats.
+func WithMetrics() ContainerOption {
+	return func(o *containerConfig) {
+		o.metrics = true
+	}
+}
+
+// Stats returns lookup counters sorted by language and namespace.
+// It returns nil if the container is created without WithMetrics.
+func (tc *TranslationContainer) Stats() []LookupStats {
+	if tc.metrics == nil {
+		return nil
+	}
+
+	var res []LookupStats
+	tc.metrics.counters.Range(func(k, v any) bool {
+		c := v.(*lookupCounters)
+		res = append(res, LookupStats{
+			Language:  k.(key).lang,
+			Namespace: k.(key).namespace,
+			Hits:      c.hits.Load(),
+			Fallbacks: c.fallbacks.Load(),
+			Misses:    c.misses.Load(),
+		})
+		return true
+	})
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Language != res[j].Language {
+			return res[i].Language < res[j].Language
+		}
+		return res[i].Namespace < res[j].Namespace
+	})
+	return res
+}
+
+// ExpvarFunc returns lookup counters as an expvar variable. Counters are
+// grouped by language code and namespace:
+//
+//	{"de": {"": {"hits": 10, "fallbacks": 2, "misses": 0}}}
+//
+// Example:
+//
+//	expvar.Publish("i18n", tc.ExpvarFunc())
+func (tc *TranslationContainer) ExpvarFunc() expvar.Func {
+	return func() any {
+		res := make(map[string]map[string]map[string]uint64)
+		for _, s := range tc.Stats() {
+			code := tc.Registry().Code(s.Language)
+			if res[code] == nil {
+				res[code] = make(map[string]map[string]uint64)
+			}
+			res[code][s.Namespace] = map[string]uint64{
+				"hits":      s.Hits,
+				"fallbacks": s.Fallbacks,
+				"misses":    s.Misses,
+			}
+		}
+		return res
+	}
+}
+
+// MetricsHandler returns a handler writing lookup counters in the
+// Prometheus text exposition format:
+//
+//	i18n_lookups_total{language="de",namespace="",result="fallback"} 2
+func (tc *TranslationContainer) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+		bw := bufio.NewWriter(w)
+		bw.WriteString("# HELP i18n_lookups_total Translation lookups by requested language, namespace and result.\n")
+		bw.WriteString("# TYPE i18n_lookups_total counter\n")
+		for _, s := range tc.Stats() {
+			code := tc.Registry().Code(s.Language)
+			for _, x := range []struct {
+				result string
+				n      uint64
+			}{{"hit", s.Hits}, {"fallback", s.Fallbacks}, {"miss", s.Misses}} {
+				bw.WriteString(`i18n_lookups_total{language="` + escapeLabel(code) +
+					`",namespace="` + escapeLabel(s.Namespace) +
+					`",result="` + x.result + `"} ` + strconv.FormatUint(x.n, 10) + "\n")
+			}
+		}
+		bw.Flush()
+	})
+}
+
+// escapeLabel escapes a Prometheus label value.
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,103 @@
+package i18n
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestContainer_Stats(t *testing.T) {
+	r := NewLanguageRegistry()
+	en, de := r.Parse("en"), r.Parse("de")
+
+	tc := NewContainer(
+		WithLanguageRegistry(r),
+		WithPrimaryLanguage(en),
+		WithMetrics(),
+		WithStorage(mapStorage{
+			"en.t18n":      "Save=Save\nCancel=Cancel\n",
+			"de.t18n":      "Save=Speichern\n",
+			"de.grid.t18n": "Exit=Beenden\n",
+		}),
+	)
+	if err := tc.ReadRegisteredFiles(); err != nil {
+		t.Fatalf("ReadRegisteredFiles failed: %v", err)
+	}
+
+	tc.Lang(de).Value("Save")
+	tc.Lang(de).Value("Save")
+	tc.Lang(de).Value("Cancel")
+	tc.Lang(de).Value("Missing")
+	tc.Namespace("grid", de).Value("Exit")
+	tc.Lang(en).Value("Save")
+
+	expected := []LookupStats{
+		{Language: en, Hits: 1},
+		{Language: de, Hits: 2, Fallbacks: 1, Misses: 1},
+		{Language: de, Namespace: "grid", Hits: 1},
+	}
+
+	got := tc.Stats()
+	if len(got) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("expected %+v, got %+v", expected[i], got[i])
+		}
+	}
+
+	if s := tc.ExpvarFunc().String(); !strings.Contains(s, `"de":{"":{"fallbacks":1,"hits":2,"misses":1},"grid":{"fallbacks":0,"hits":1,"misses":0}}`) {
+		t.Errorf("unexpected expvar value: %s", s)
+	}
+
+	rec := httptest.NewRecorder()
+	tc.MetricsHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	for _, line := range []string{
+		"# TYPE i18n_lookups_total counter\n",
+		`i18n_lookups_total{language="de",namespace="",result="fallback"} 1` + "\n",
+		`i18n_lookups_total{language="de",namespace="grid",result="hit"} 1` + "\n",
+		`i18n_lookups_total{language="en",namespace="",result="miss"} 0` + "\n",
+	} {
+		if !strings.Contains(body, line) {
+			t.Errorf("expected %q in:\n%s", line, body)
+		}
+	}
+}
+
+func TestContainer_StatsDisabled(t *testing.T) {
+	tc := NewContainer(WithStorage(mapStorage{"en.t18n": "Save=Save\n"}))
+	if err := tc.ReadRegisteredFiles(); err != nil {
+		t.Fatalf("ReadRegisteredFiles failed: %v", err)
+	}
+	tc.Lang(defaultRegistry.Parse("en")).Value("Save")
+	if got := tc.Stats(); got != nil {
+		t.Errorf("expected nil, got %v", got)
+	}
+}
+
+func TestContainer_StatsOncePerCall(t *testing.T) {
+	r := NewLanguageRegistry()
+	cs := r.Parse("cs")
+
+	tc := NewContainer(
+		WithLanguageRegistry(r),
+		WithMetrics(),
+		WithStorage(mapStorage{
+			"cs.t18n": "Save=Uložit\n",
+		}),
+	)
+	if err := tc.ReadRegisteredFiles(); err != nil {
+		t.Fatalf("ReadRegisteredFiles failed: %v", err)
+	}
+
+	tr := tc.Lang(cs)
+	tr.Format("Missing", nil)
+	tr.Plural("Save", 3)
+
+	expected := []LookupStats{{Language: cs, Hits: 1, Misses: 1}}
+	if got := tc.Stats(); len(got) != 1 || got[0] != expected[0] {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}
@@ -0,0 +1,138 @@
+package i18n
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"sync"
+)
+
+// MissingHandler is called once per lookup call, like Value or Plural,
+// of the key in the namespace not translated to the requested language li.
+// The resolved language is the language the returned value is found in by
+// the fallback chain, or Unknown if the key is not found at all.
+//
+// Keys having a context are passed like "Open@button".
+// The handler is called concurrently and shall not block.
+type MissingHandler func(li Language, namespace, key string, resolved Language)
+
+// MissingCollector collects distinct missing keys by language and
+// namespace, so they can be given to translators as .t18n stubs.
+//
+// Example:
+//
+//	mc := i18n.NewMissingCollector()
+//	tc := i18n.NewContainer(i18n.WithMissingHandler(mc.Handle))
+type MissingCollector struct {
+	mux sync.Mutex
+
+	// missing maps language and namespace to missing keys and their
+	// resolved languages.
+	missing map[key]map[string]Language
+}
+
+// NewMissingCollector returns an empty collector.
+func NewMissingCollector() *MissingCollector {
+	return &MissingCollector{missing: make(map[key]map[string]Language)}
+}
+
+// Handle records the missing key, it's a MissingHandler.
+func (c *MissingCollector) Handle(li Language, namespace, id string, resolved Language) {
+	k := key{lang: li, namespace: namespace}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	keys, ok := c.missing[k]
+	if !ok {
+		keys = make(map[string]Language)
+		c.missing[k] = keys
+	}
+	keys[id] = resolved
+}
+
+// Keys returns sorted missing keys of the language in the namespace.
+func (c *MissingCollector) Keys(li Language, namespace string) []string {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	keys := c.missing[key{lang: li, namespace: namespace}]
+	res := make([]string, 0, len(keys))
+	for id := range keys {
+		res = append(res, id)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Reset forgets all collected keys.
+func (c *MissingCollector) Reset() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.missing = make(map[key]map[string]Language)
+}
+
+// WriteStub writes missing keys of the language in the namespace as
+// a .t18n file with empty values, see EncodeItems. A key found in
+// a fallback language is preceded by a comment naming the language:
+//
+//	# found in en
+//	Save=
+func (c *MissingCollector) WriteStub(w io.Writer, r *LanguageRegistry, li Language, namespace string) error {
+	c.mux.Lock()
+	keys := c.missing[key{lang: li, namespace: namespace}]
+	resolved := make(map[string]Language, len(keys))
+	for id, rl := range keys {
+		resolved[id] = rl
+	}
+	c.mux.Unlock()
+
+	ids := make([]string, 0, len(resolved))
+	for id := range resolved {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	items := make([]Item, len(ids))
+	for i, id := range ids {
+		items[i].Key, items[i].Context = splitContext(id)
+	}
+
+	var buf bytes.Buffer
+	err := encodeItems(&buf, items, func(i int) []string {
+		if rl := resolved[ids[i]]; rl != Unknown {
+			return []string{"found in " + r.Code(rl)}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// Stubs returns .t18n stubs of all collected keys by file names like
+// "de.t18n" and "de.customer1.t18n". Language codes are taken from
+// the registry r.
+func (c *MissingCollector) Stubs(r *LanguageRegistry) map[string][]byte {
+	c.mux.Lock()
+	var keys []key
+	for k := range c.missing {
+		keys = append(keys, k)
+	}
+	c.mux.Unlock()
+
+	res := make(map[string][]byte, len(keys))
+	for _, k := range keys {
+		name := r.Code(k.lang) + ".t18n"
+		if k.namespace != "" {
+			name = r.Code(k.lang) + "." + k.namespace + ".t18n"
+		}
+
+		var buf bytes.Buffer
+		_ = c.WriteStub(&buf, r, k.lang, k.namespace)
+		res[name] = buf.Bytes()
+	}
+	return res
+}
@@ -0,0 +1,160 @@
+package i18n
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestMissingHandler(t *testing.T) {
+	r := NewLanguageRegistry()
+	en, de := r.Parse("en"), r.Parse("de")
+
+	var got []string
+	tc := NewContainer(
+		WithLanguageRegistry(r),
+		WithPrimaryLanguage(en),
+		WithMissingHandler(func(li Language, namespace, key string, resolved Language) {
+			got = append(got, r.Code(li)+"|"+namespace+"|"+key+"|"+r.Code(resolved))
+		}),
+		WithStorage(mapStorage{
+			"en.t18n": "Save=Save\nCancel=Cancel\nOpen@button=Open\n",
+			"de.t18n": "Save=Speichern\n",
+		}),
+	)
+	if err := tc.ReadRegisteredFiles(); err != nil {
+		t.Fatalf("ReadRegisteredFiles failed: %v", err)
+	}
+
+	tc.Lang(de).Value("Save")
+	tc.Lang(de).Value("Cancel")
+	tc.Lang(de).ValueCtx("button", "Open")
+	tc.Namespace("grid", en).Value("Missing")
+
+	expected := []string{
+		"de||Cancel|en",
+		"de||Open@button|en",
+		"en|grid|Missing|" + UnknownLanguageCode,
+	}
+	if strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestMissingHandler_OncePerCall(t *testing.T) {
+	r := NewLanguageRegistry()
+	en, cs := r.Parse("en"), r.Parse("cs")
+
+	var got []string
+	tc := NewContainer(
+		WithLanguageRegistry(r),
+		WithPrimaryLanguage(en),
+		WithMessageFormat(),
+		WithMissingHandler(func(li Language, namespace, key string, resolved Language) {
+			got = append(got, r.Code(li)+"|"+key+"|"+r.Code(resolved))
+		}),
+		WithStorage(mapStorage{
+			"en.t18n": "Save=Save\nOpen@button=Open\n",
+			"cs.t18n": "Save=Uložit\nOpen=Otevřít\n",
+		}),
+	)
+	if err := tc.ReadRegisteredFiles(); err != nil {
+		t.Fatalf("ReadRegisteredFiles failed: %v", err)
+	}
+
+	tr := tc.Lang(cs)
+	tr.Format("Missing", nil)
+	tr.Message("Missing", nil)
+	tr.Plural("Save", 3)
+	tr.Select("Save", "female")
+	tr.ValueCtx("button", "Save")
+	if got := tr.ValueCtx("button", "Open"); got != "Otevřít" {
+		t.Errorf("expected 'Otevřít', got %q", got)
+	}
+
+	expected := []string{
+		"cs|Missing|" + UnknownLanguageCode,
+		"cs|Missing|" + UnknownLanguageCode,
+	}
+	if strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestMissingCollector(t *testing.T) {
+	r := NewLanguageRegistry()
+	en, de := r.Parse("en"), r.Parse("de")
+
+	mc := NewMissingCollector()
+	tc := NewContainer(
+		WithLanguageRegistry(r),
+		WithPrimaryLanguage(en),
+		WithMissingHandler(mc.Handle),
+		WithStorage(mapStorage{
+			"en.t18n": "Save=Save\nCancel=Cancel\nFiles[one]=# file\nFiles[other]=# files\n",
+			"de.t18n": "Save=Speichern\n",
+		}),
+	)
+	if err := tc.ReadRegisteredFiles(); err != nil {
+		t.Fatalf("ReadRegisteredFiles failed: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		tc.Lang(de).Value("Save")
+		tc.Lang(de).Value("Cancel")
+		tc.Lang(de).Plural("Files", i)
+		tc.Lang(de).Value("Exit")
+		tc.Namespace("grid", de).Value("Exit")
+	}
+
+	if got := strings.Join(mc.Keys(de, ""), ","); got != "Cancel,Exit,Files" {
+		t.Errorf("expected 'Cancel,Exit,Files', got %q", got)
+	}
+
+	stubs := mc.Stubs(r)
+	if len(stubs) != 2 {
+		t.Fatalf("expected 2 stubs, got %d", len(stubs))
+	}
+	if got := string(stubs["de.t18n"]); got != "# found in en\nCancel=\nExit=\n# found in en\nFiles=\n" {
+		t.Errorf("unexpected de.t18n stub: %q", got)
+	}
+	if got := string(stubs["de.grid.t18n"]); got != "Exit=\n" {
+		t.Errorf("unexpected de.grid.t18n stub: %q", got)
+	}
+
+	mc.Reset()
+	if got := mc.Keys(de, ""); len(got) != 0 {
+		t.Errorf("expected no keys after Reset, got %v", got)
+	}
+}
+
+func TestMissingCollector_StubKeys(t *testing.T) {
+	r := NewLanguageRegistry()
+	de := r.Parse("de")
+
+	mc := NewMissingCollector()
+	for _, id := range []string{"a=b", "#hash", "// c", `"q"`, " padded ", "Open@button"} {
+		mc.Handle(de, "", id, Unknown)
+	}
+
+	var buf bytes.Buffer
+	if err := mc.WriteStub(&buf, r, de, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var p DefaultParser
+	items, diags, err := p.ParseFileDiagnostics(buf.Bytes())
+	if err != nil || len(diags) != 0 {
+		t.Fatalf("unexpected error: %v %v", err, diags)
+	}
+	var got []string
+	for _, item := range items {
+		if item.Value != "" {
+			t.Errorf("expected empty value of %q, got %q", item.Key, item.Value)
+		}
+		got = append(got, displayID(item.id()))
+	}
+	if strings.Join(got, ",") != strings.Join(mc.Keys(de, ""), ",") {
+		t.Errorf("expected keys %q, got %q in\n%s", mc.Keys(de, ""), got, buf.String())
+	}
+}
@@ -0,0 +1,332 @@
+package i18n
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NumberFormat formats numbers by CLDR data of a language: decimal and
+// grouping symbols, percent and currency patterns, compact forms.
+//
+// Numbers can be of any integer or float type, or decimal strings like
+// "1234.50". Other values are formatted by fmt.Sprint.
+type NumberFormat struct {
+	d *numberData
+}
+
+// Numbers returns the number format of the language li of the default registry.
+func Numbers(li Language) NumberFormat {
+	return defaultRegistry.Numbers(li)
+}
+
+// Numbers returns the number format of the language li.
+// Languages without CLDR data use parent language data (de-LU uses de),
+// or root data: 1,234.5.
+//
+// Built-in data covers cs, da, de, de-AT, de-CH, en, en-AU, en-CA, en-GB,
+// en-IN, es, es-419, fi, fr, fr-CA, hi, it, ja, ko, nb, nl, pl, pt, pt-PT,
+// ru, sr, sr-Latn, sv, tr, uk and zh.
+func (r *LanguageRegistry) Numbers(li Language) NumberFormat {
+	return newNumberFormat(r.Code(li))
+}
+
+// Numbers returns the number format of the requested language, so
+// the negotiated language drives both texts and numbers.
+func (tr TranslationRequest) Numbers() NumberFormat {
+	if tr.tc == nil {
+		return defaultRegistry.Numbers(tr.lang)
+	}
+	return tr.tc.Registry().Numbers(tr.lang)
+}
+
+func newNumberFormat(code string) NumberFormat {
+	return NumberFormat{d: resolveNumberData(code)}
+}
+
+// Decimal formats n with up to 3 fraction digits: 1,234.568 in English,
+// 1.234,568 in German.
+func (nf NumberFormat) Decimal(n any) string {
+	pat := parseNumberPattern(nf.d.decimalPattern)
+	return nf.format(n, pat, pat.minFrac, pat.maxFrac, 0, "")
+}
+
+// DecimalFixed formats n with exactly digits fraction digits.
+func (nf NumberFormat) DecimalFixed(n any, digits int) string {
+	if digits < 0 {
+		digits = 0
+	}
+	return nf.format(n, parseNumberPattern(nf.d.decimalPattern), digits, digits, 0, "")
+}
+
+// Percent formats n multiplied by 100 as percents: 0.25 is 25% in
+// English, 25 % in German.
+func (nf NumberFormat) Percent(n any) string {
+	pat := parseNumberPattern(nf.d.percentPattern)
+	return nf.format(n, pat, pat.minFrac, pat.maxFrac, 2, "")
+}
+
+// Currency formats n as the amount in the currency given by ISO 4217
+// code, like "EUR". The symbol and its placement depend on the
+// language: €1,234.50 in English, 1.234,50 € in German.
+func (nf NumberFormat) Currency(n any, currency string) string {
+	currency = strings.ToUpper(currency)
+
+	digits, ok := currencyDigits[currency]
+	if !ok {
+		digits = 2
+	}
+	return nf.format(n, parseNumberPattern(nf.d.currencyPattern), digits, digits, 0, nf.currencySymbol(currency))
+}
+
+// currencySymbol returns the symbol of the currency in the language.
+func (nf NumberFormat) currencySymbol(currency string) string {
+	if s, ok := nf.d.currencies[currency]; ok {
+		return s
+	}
+	if s, ok := currencySymbols[currency]; ok {
+		return s
+	}
+	return currency
+}
+
+// Compact formats n in the short compact form: 1.2K in English,
+// 1,2 Mio. in German, 1.2万 in Japanese. Numbers are rounded to
+// integers keeping at least 2 significant digits.
+func (nf NumberFormat) Compact(n any) string {
+	s, ok := decimalString(n)
+	if !ok {
+		return fmt.Sprint(n)
+	}
+	f, _ := strconv.ParseFloat(s, 64)
+	neg := f < 0
+	f = math.Abs(f)
+
+	patterns := nf.d.compact
+	idx := -1
+	for i := range patterns {
+		if f >= patterns[i].divisor {
+			idx = i
+		}
+	}
+
+	var q float64
+	for {
+		q = f
+		if idx >= 0 {
+			q = f / patterns[idx].divisor
+		}
+		q = compactRound(q)
+
+		// 999,999 is rounded to 1000K, use 1M instead. A number in
+		// a magnitude without pattern, like thousands in German, has
+		// more than 4 digits and rolls over if it's rounded to 1 in the
+		// next magnitude: 999,999 is 1 Mio.
+		next := idx + 1
+		if next < len(patterns) {
+			div := 1.0
+			if idx >= 0 {
+				div = patterns[idx].divisor
+			}
+			if q*div >= patterns[next].divisor ||
+				q >= 1e4 && compactRound(f/patterns[next].divisor) >= 1 {
+				idx = next
+				continue
+			}
+		}
+		break
+	}
+
+	pat := parseNumberPattern(nf.d.decimalPattern)
+	num := nf.format(strconv.FormatFloat(q, 'f', -1, 64), pat, 0, 2, 0, "")
+	if idx >= 0 {
+		p := patterns[idx].pattern
+		start := strings.IndexByte(p, '0')
+		end := start + len(p[start:]) - len(strings.TrimLeft(p[start:], "0"))
+		num = p[:start] + num + p[end:]
+	}
+	if neg && q != 0 {
+		num = nf.d.minus + num
+	}
+	return num
+}
+
+// compactRound rounds q to an integer keeping at least 2 significant digits.
+func compactRound(q float64) float64 {
+	switch {
+	case q >= 10:
+		return math.Round(q)
+	case q >= 1:
+		return math.Round(q*10) / 10
+	}
+	return math.Round(q*100) / 100
+}
+
+// format formats n by the pattern. The decimal point of n is moved by
+// shift digits to the right before rounding. symbol replaces "¤".
+func (nf NumberFormat) format(n any, pat numberPattern, minFrac, maxFrac, shift int, symbol string) string {
+	s, ok := decimalString(n)
+	if !ok {
+		return fmt.Sprint(n)
+	}
+
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	if shift > 0 {
+		s = shiftDecimal(s, shift)
+	}
+
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if len(fracPart) > maxFrac {
+		f, _ := strconv.ParseFloat(s, 64)
+		intPart, fracPart, _ = strings.Cut(strconv.FormatFloat(f, 'f', maxFrac, 64), ".")
+	}
+
+	fracPart = strings.TrimRight(fracPart, "0")
+	if len(fracPart) < minFrac {
+		fracPart += strings.Repeat("0", minFrac-len(fracPart))
+	}
+	intPart = strings.TrimLeft(intPart, "0")
+	if len(intPart) < pat.minInt {
+		intPart = strings.Repeat("0", pat.minInt-len(intPart)) + intPart
+	}
+	if strings.Trim(intPart+fracPart, "0") == "" {
+		neg = false
+	}
+
+	var sb strings.Builder
+	if neg {
+		sb.WriteString(nf.d.minus)
+	}
+	sb.WriteString(nf.affix(pat.prefix, symbol, true))
+	sb.WriteString(nf.group(intPart, pat))
+	if fracPart != "" {
+		sb.WriteString(nf.d.decimal)
+		sb.WriteString(fracPart)
+	}
+	sb.WriteString(nf.affix(pat.suffix, symbol, false))
+	return sb.String()
+}
+
+// affix replaces pattern symbols in the prefix or the suffix.
+// A currency symbol ending with a letter is separated from digits
+// by a no-break space: CHF 12.00.
+func (nf NumberFormat) affix(s, symbol string, prefix bool) string {
+	if s == "" {
+		return s
+	}
+	s = strings.ReplaceAll(s, "%", nf.d.percent)
+
+	if !strings.Contains(s, "¤") {
+		return s
+	}
+	if prefix && strings.HasSuffix(s, "¤") {
+		if r, _ := utf8.DecodeLastRuneInString(symbol); unicode.IsLetter(r) {
+			symbol += "\u00a0"
+		}
+	}
+	if !prefix && strings.HasPrefix(s, "¤") {
+		if r, _ := utf8.DecodeRuneInString(symbol); unicode.IsLetter(r) {
+			symbol = "\u00a0" + symbol
+		}
+	}
+	return strings.ReplaceAll(s, "¤", symbol)
+}
+
+// group inserts grouping separators into integer digits.
+func (nf NumberFormat) group(digits string, pat numberPattern) string {
+	if pat.grouping == 0 || len(digits) < pat.grouping+nf.d.minGrouping {
+		return digits
+	}
+
+	size2 := pat.grouping2
+	if size2 == 0 {
+		size2 = pat.grouping
+	}
+
+	groups := []string{digits[len(digits)-pat.grouping:]}
+	digits = digits[:len(digits)-pat.grouping]
+	for len(digits) > size2 {
+		groups = append(groups, digits[len(digits)-size2:])
+		digits = digits[:len(digits)-size2]
+	}
+	if digits != "" {
+		groups = append(groups, digits)
+	}
+
+	var sb strings.Builder
+	for i := len(groups) - 1; i >= 0; i-- {
+		sb.WriteString(groups[i])
+		if i > 0 {
+			sb.WriteString(nf.d.group)
+		}
+	}
+	return sb.String()
+}
+
+// decimalString converts n to a decimal string like "-1234.5".
+func decimalString(n any) (string, bool) {
+	switch x := n.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(x), true
+	case float32:
+		if math.IsNaN(float64(x)) || math.IsInf(float64(x), 0) {
+			return "", false
+		}
+		return strconv.FormatFloat(float64(x), 'f', -1, 32), true
+	case float64:
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			return "", false
+		}
+		return strconv.FormatFloat(x, 'f', -1, 64), true
+	case string:
+		f, err := strconv.ParseFloat(x, 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return "", false
+		}
+		if strings.ContainsAny(x, "eExX") || strings.HasPrefix(x, "+") {
+			return strconv.FormatFloat(f, 'f', -1, 64), true
+		}
+		return x, true
+	}
+	return "", false
+}
+
+// numberPattern is a parsed CLDR number pattern like "#,##0.00 ¤".
+type numberPattern struct {
+	prefix, suffix   string
+	minInt           int
+	minFrac, maxFrac int
+
+	// grouping is the size of the primary group, grouping2 is the size
+	// of other groups if it differs: 3 and 2 in "#,##,##0".
+	grouping, grouping2 int
+}
+
+func parseNumberPattern(p string) numberPattern {
+	var res numberPattern
+
+	start := strings.IndexAny(p, "#0,.")
+	if start == -1 {
+		res.prefix = p
+		return res
+	}
+	end := strings.LastIndexAny(p, "#0,.") + 1
+	res.prefix, res.suffix = p[:start], p[end:]
+
+	intPat, fracPat, _ := strings.Cut(p[start:end], ".")
+	res.minInt = strings.Count(intPat, "0")
+	res.minFrac = strings.Count(fracPat, "0")
+	res.maxFrac = len(fracPat)
+
+	if last := strings.LastIndexByte(intPat, ','); last != -1 {
+		res.grouping = len(intPat) - last - 1
+		if prev := strings.LastIndexByte(intPat[:last], ','); prev != -1 && last-prev-1 != res.grouping {
+			res.grouping2 = last - prev - 1
+		}
+	}
+	return res
+}
@@ -0,0 +1,97 @@
+package i18n
+
+import "testing"
+
+func TestNumberFormat(t *testing.T) {
+	r := NewLanguageRegistry()
+
+	tests := []struct {
+		code     string
+		format   func(nf NumberFormat) string
+		expected string
+	}{
+		{"en", func(nf NumberFormat) string { return nf.Decimal(1234567.891) }, "1,234,567.891"},
+		{"en", func(nf NumberFormat) string { return nf.Decimal(-0.0001) }, "0"},
+		{"en", func(nf NumberFormat) string { return nf.Decimal(-1234) }, "-1,234"},
+		{"en", func(nf NumberFormat) string { return nf.Decimal("1234.50") }, "1,234.5"},
+		{"en", func(nf NumberFormat) string { return nf.DecimalFixed(2.5, 2) }, "2.50"},
+		{"de", func(nf NumberFormat) string { return nf.Decimal(1234567.891) }, "1.234.567,891"},
+		{"de-LU", func(nf NumberFormat) string { return nf.Decimal(1234.5) }, "1.234,5"},
+		{"de-CH", func(nf NumberFormat) string { return nf.Decimal(1234.5) }, "1’234.5"},
+		{"fr", func(nf NumberFormat) string { return nf.Decimal(1234.5) }, "1\u202f234,5"},
+		{"es", func(nf NumberFormat) string { return nf.Decimal(1234) }, "1234"},
+		{"es", func(nf NumberFormat) string { return nf.Decimal(12345) }, "12.345"},
+		{"hi", func(nf NumberFormat) string { return nf.Decimal(12345678) }, "1,23,45,678"},
+		{"sv", func(nf NumberFormat) string { return nf.Decimal(-5) }, "\u22125"},
+		{"xx", func(nf NumberFormat) string { return nf.Decimal(1234.5) }, "1,234.5"},
+		{"en", func(nf NumberFormat) string { return nf.Decimal("n/a") }, "n/a"},
+
+		{"en", func(nf NumberFormat) string { return nf.Percent(0.256) }, "26%"},
+		{"de", func(nf NumberFormat) string { return nf.Percent(0.25) }, "25\u00a0%"},
+		{"tr", func(nf NumberFormat) string { return nf.Percent(0.25) }, "%25"},
+
+		{"en", func(nf NumberFormat) string { return nf.Currency(1234.5, "USD") }, "$1,234.50"},
+		{"en", func(nf NumberFormat) string { return nf.Currency(-3, "eur") }, "-€3.00"},
+		{"en", func(nf NumberFormat) string { return nf.Currency(1234.6, "JPY") }, "¥1,235"},
+		{"en", func(nf NumberFormat) string { return nf.Currency(12, "CHF") }, "CHF\u00a012.00"},
+		{"en-CA", func(nf NumberFormat) string { return nf.Currency(5, "USD") }, "US$5.00"},
+		{"de", func(nf NumberFormat) string { return nf.Currency(1234.5, "EUR") }, "1.234,50\u00a0€"},
+		{"de", func(nf NumberFormat) string { return nf.Currency(1234.5, "USD") }, "1.234,50\u00a0US$"},
+		{"cs", func(nf NumberFormat) string { return nf.Currency(100, "CZK") }, "100,00\u00a0Kč"},
+		{"ja", func(nf NumberFormat) string { return nf.Currency(100, "JPY") }, "￥100"},
+
+		{"en", func(nf NumberFormat) string { return nf.Compact(999) }, "999"},
+		{"en", func(nf NumberFormat) string { return nf.Compact(1234) }, "1.2K"},
+		{"en", func(nf NumberFormat) string { return nf.Compact(12345) }, "12K"},
+		{"en", func(nf NumberFormat) string { return nf.Compact(-2500000) }, "-2.5M"},
+		{"en", func(nf NumberFormat) string { return nf.Compact(999999) }, "1M"},
+		{"en", func(nf NumberFormat) string { return nf.Compact(1.5e15) }, "1,500T"},
+		{"de", func(nf NumberFormat) string { return nf.Compact(1234) }, "1.234"},
+		{"de", func(nf NumberFormat) string { return nf.Compact(1200000) }, "1,2\u00a0Mio."},
+		{"de", func(nf NumberFormat) string { return nf.Compact(999999) }, "1\u00a0Mio."},
+		{"de", func(nf NumberFormat) string { return nf.Compact(990000) }, "990.000"},
+		{"es", func(nf NumberFormat) string { return nf.Compact(999999999999) }, "1\u00a0B"},
+		{"es-419", func(nf NumberFormat) string { return nf.Decimal(1234567.891) }, "1,234,567.891"},
+		{"es-419", func(nf NumberFormat) string { return nf.Decimal(1234) }, "1,234"},
+		{"es-419", func(nf NumberFormat) string { return nf.Currency(1234.5, "EUR") }, "€1,234.50"},
+		{"es", func(nf NumberFormat) string { return nf.Currency(1234.5, "EUR") }, "1234,50\u00a0€"},
+		{"sr", func(nf NumberFormat) string { return nf.Decimal(1234567.891) }, "1.234.567,891"},
+		{"sr", func(nf NumberFormat) string { return nf.Compact(2500) }, "2,5\u00a0хиљ."},
+		{"sr-Latn", func(nf NumberFormat) string { return nf.Decimal(1234567.891) }, "1.234.567,891"},
+		{"sr-Latn", func(nf NumberFormat) string { return nf.Currency(1234.5, "EUR") }, "1.234,50\u00a0€"},
+		{"sr-Latn", func(nf NumberFormat) string { return nf.Compact(2500) }, "2,5\u00a0hilj."},
+		{"ja", func(nf NumberFormat) string { return nf.Compact(9999) }, "9,999"},
+		{"ja", func(nf NumberFormat) string { return nf.Compact(12345) }, "1.2万"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.format(r.Numbers(r.Parse(tt.code))); got != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.code, tt.expected, got)
+		}
+	}
+}
+
+func TestTranslationRequest_Numbers(t *testing.T) {
+	r := NewLanguageRegistry()
+	de := r.Parse("de-AT")
+
+	tc := NewContainer(
+		WithLanguageRegistry(r),
+		WithStorage(mapStorage{
+			"de.t18n": "Total={n, number, ::currency/EUR} für {count, plural, one {# Artikel} other {# Artikel}}\n",
+		}),
+	)
+	if err := tc.ReadRegisteredFiles(); err != nil {
+		t.Fatalf("ReadRegisteredFiles failed: %v", err)
+	}
+
+	if got := tc.Lang(de).Numbers().Decimal(1234.5); got != "1\u00a0234,5" {
+		t.Errorf("expected '1\u00a0234,5', got %q", got)
+	}
+
+	// the message is found in de, so it's formatted by de rules
+	got, err := tc.Lang(de).MessageArgs("Total", "n", 1234.5, "count", 1200)
+	if expected := "1.234,50\u00a0€ für 1.200 Artikel"; err != nil || got != expected {
+		t.Errorf("expected %q, got %q, %v", expected, got, err)
+	}
+}
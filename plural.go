@@ -0,0 +1,102 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Cardinal returns CLDR cardinal plural category of the number n in
+// the language li of the default registry.
+func Cardinal(li Language, n any) PluralCategory {
+	return defaultRegistry.Cardinal(li, n)
+}
+
+// Cardinal returns CLDR cardinal plural category of the number n in
+// the language li. See newPluralOperands for supported types of n.
+// Returns PluralOther if n is not a number.
+func (r *LanguageRegistry) Cardinal(li Language, n any) PluralCategory {
+	return pluralCategory(cardinalRuleSet(), r.Code(li), n)
+}
+
+// Ordinal returns CLDR ordinal plural category of the number n in
+// the language li of the default registry.
+func Ordinal(li Language, n any) PluralCategory {
+	return defaultRegistry.Ordinal(li, n)
+}
+
+// Ordinal returns CLDR ordinal plural category of the number n in
+// the language li, like PluralTwo for 22 in English (22nd).
+// Returns PluralOther if n is not a number.
+func (r *LanguageRegistry) Ordinal(li Language, n any) PluralCategory {
+	return pluralCategory(ordinalRuleSet(), r.Code(li), n)
+}
+
+// pluralCategory returns category of n by rules of the language code.
+func pluralCategory(set pluralRuleSet, code string, n any) PluralCategory {
+	rules := set.rules(code)
+	if len(rules) == 0 {
+		return PluralOther
+	}
+
+	o, err := newPluralOperands(n)
+	if err != nil {
+		return PluralOther
+	}
+	return rules.category(&o)
+}
+
+// Plural returns the plural variant of the key for the number n.
+//
+// The category of n is calculated by CLDR cardinal rules of the language
+// the item is found in. The variant of the category is returned, if it's
+// absent, the variant "other". Items without any of them are skipped
+// and the fallback chain is walked further. If no variant is found,
+// Value(key) is returned.
+//
+// Every "#" in the variant is replaced by n.
+//
+// Variants are declared in .t18n files as:
+//
+//	Files[one]=# file
+//	Files[other]=# files
+func (tr TranslationRequest) Plural(key string, n any) string {
+	return tr.pluralVariant(key, n, cardinalRuleSet())
+}
+
+// Ordinal returns the ordinal variant of the key for the number n.
+// It works like Plural, but the category is calculated by CLDR ordinal
+// rules of the language the item is found in.
+//
+// Variants are declared in .t18n files as:
+//
+//	Place[one]=#st
+//	Place[two]=#nd
+//	Place[few]=#rd
+//	Place[other]=#th
+func (tr TranslationRequest) Ordinal(key string, n any) string {
+	return tr.pluralVariant(key, n, ordinalRuleSet())
+}
+
+// pluralVariant returns the plural variant of the key selected by the rule set.
+func (tr TranslationRequest) pluralVariant(key string, n any, set pluralRuleSet) string {
+	if tr.tc == nil {
+		return key
+	}
+
+	var variant string
+	_, li, ok := tr.lookupMatch(key, func(item *Item, li Language) bool {
+		cat := pluralCategory(set, tr.tc.Registry().Code(li), n)
+		var ok bool
+		if variant, ok = item.Variant(cat.String()); ok {
+			return true
+		}
+		variant, ok = item.Variant(PluralOther.String())
+		return ok
+	})
+	if !ok {
+		return tr.value(key).Value
+	}
+	tr.report(key, li, true)
+
+	return strings.ReplaceAll(variant, "#", fmt.Sprint(n))
+}
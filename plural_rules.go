@@ -0,0 +1,346 @@
+package i18n
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// PluralCategory is a CLDR plural category.
+type PluralCategory int8
+
+const (
+	PluralOther PluralCategory = iota
+	PluralZero
+	PluralOne
+	PluralTwo
+	PluralFew
+	PluralMany
+)
+
+var pluralCategoryNames = [...]string{"other", "zero", "one", "two", "few", "many"}
+
+// String implements fmt.Stringer interface.
+// The names are used as variant names in .t18n files: Files[one]=# file
+func (c PluralCategory) String() string {
+	if c < 0 || int(c) >= len(pluralCategoryNames) {
+		return "other"
+	}
+	return pluralCategoryNames[c]
+}
+
+// ParsePluralCategory returns the category by name like "few".
+func ParsePluralCategory(name string) (PluralCategory, bool) {
+	for i, n := range pluralCategoryNames {
+		if n == name {
+			return PluralCategory(i), true
+		}
+	}
+	return PluralOther, false
+}
+
+// pluralOperands holds operands of CLDR plural rules.
+// See https://unicode.org/reports/tr35/tr35-numbers.html#Operands
+type pluralOperands struct {
+	n float64 // absolute value of the source number
+	i float64 // integer digits of n
+	v float64 // number of visible fraction digits in n, with trailing zeros
+	w float64 // number of visible fraction digits in n, without trailing zeros
+	f float64 // visible fraction digits in n, with trailing zeros
+	t float64 // visible fraction digits in n, without trailing zeros
+	e float64 // compact decimal exponent
+}
+
+// newPluralOperands returns operands of the number n.
+// n can be any integer or float type, or a decimal string like "1.50"
+// which keeps visible fraction digits.
+func newPluralOperands(n any) (pluralOperands, error) {
+	var s string
+	switch x := n.(type) {
+	case int:
+		s = strconv.FormatInt(int64(x), 10)
+	case int8:
+		s = strconv.FormatInt(int64(x), 10)
+	case int16:
+		s = strconv.FormatInt(int64(x), 10)
+	case int32:
+		s = strconv.FormatInt(int64(x), 10)
+	case int64:
+		s = strconv.FormatInt(x, 10)
+	case uint:
+		s = strconv.FormatUint(uint64(x), 10)
+	case uint8:
+		s = strconv.FormatUint(uint64(x), 10)
+	case uint16:
+		s = strconv.FormatUint(uint64(x), 10)
+	case uint32:
+		s = strconv.FormatUint(uint64(x), 10)
+	case uint64:
+		s = strconv.FormatUint(x, 10)
+	case float32:
+		s = strconv.FormatFloat(float64(x), 'f', -1, 32)
+	case float64:
+		s = strconv.FormatFloat(x, 'f', -1, 64)
+	case string:
+		s = x
+	case fmt.Stringer:
+		s = x.String()
+	default:
+		return pluralOperands{}, fmt.Errorf("plural operands: unsupported type %T", n)
+	}
+	return parsePluralOperands(s)
+}
+
+// parsePluralOperands parses a decimal number like "-12.50" or "1.2c6".
+func parsePluralOperands(s string) (pluralOperands, error) {
+	var res pluralOperands
+
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+	if pos := strings.IndexAny(s, "ce"); pos != -1 {
+		e, err := strconv.Atoi(s[pos+1:])
+		if err != nil {
+			return res, fmt.Errorf("plural operands: invalid number %q", s)
+		}
+		res.e = float64(e)
+		s = shiftDecimal(s[:pos], e)
+	}
+
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if intPart == "" || !isDigit(intPart) || !isDigit(fracPart) {
+		return res, fmt.Errorf("plural operands: invalid number %q", s)
+	}
+
+	res.i, _ = strconv.ParseFloat(intPart, 64)
+	res.v = float64(len(fracPart))
+	if fracPart != "" {
+		res.f, _ = strconv.ParseFloat(fracPart, 64)
+	}
+	trimmed := strings.TrimRight(fracPart, "0")
+	res.w = float64(len(trimmed))
+	if trimmed != "" {
+		res.t, _ = strconv.ParseFloat(trimmed, 64)
+	}
+	res.n, _ = strconv.ParseFloat(intPart+"."+fracPart+"0", 64)
+	return res, nil
+}
+
+// shiftDecimal moves the decimal point of s by e digits to the right.
+func shiftDecimal(s string, e int) string {
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	for ; e > 0; e-- {
+		if fracPart == "" {
+			intPart += "0"
+			continue
+		}
+		intPart += fracPart[:1]
+		fracPart = fracPart[1:]
+	}
+	intPart = strings.TrimLeft(intPart, "0")
+	if intPart == "" {
+		intPart = "0"
+	}
+	if fracPart == "" {
+		return intPart
+	}
+	return intPart + "." + fracPart
+}
+
+func (o *pluralOperands) get(operand byte) float64 {
+	switch operand {
+	case 'n':
+		return o.n
+	case 'i':
+		return o.i
+	case 'v':
+		return o.v
+	case 'w':
+		return o.w
+	case 'f':
+		return o.f
+	case 't':
+		return o.t
+	case 'c', 'e':
+		return o.e
+	}
+	return 0
+}
+
+type pluralRange struct {
+	from, to float64
+}
+
+// pluralRelation is a relation like "i % 10 = 2..4,9".
+type pluralRelation struct {
+	operand byte
+	mod     float64
+	negate  bool
+	ranges  []pluralRange
+}
+
+func (r *pluralRelation) eval(o *pluralOperands) bool {
+	x := o.get(r.operand)
+	if r.mod != 0 {
+		x = math.Mod(x, r.mod)
+	}
+
+	match := false
+	if x == math.Trunc(x) {
+		for _, rg := range r.ranges {
+			if x >= rg.from && x <= rg.to {
+				match = true
+				break
+			}
+		}
+	}
+	return match != r.negate
+}
+
+// pluralCondition is a disjunction of conjunctions of relations.
+type pluralCondition [][]pluralRelation
+
+func (c pluralCondition) eval(o *pluralOperands) bool {
+	for _, and := range c {
+		ok := true
+		for i := range and {
+			if !and[i].eval(o) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+type pluralRule struct {
+	category  PluralCategory
+	condition pluralCondition
+}
+
+// pluralRules holds rules of a locale in the order of evaluation.
+// If no rule matches, the category is PluralOther.
+type pluralRules []pluralRule
+
+func (rules pluralRules) category(o *pluralOperands) PluralCategory {
+	for i := range rules {
+		if rules[i].condition.eval(o) {
+			return rules[i].category
+		}
+	}
+	return PluralOther
+}
+
+// categories returns categories used by the rules, PluralOther is the last one.
+func (rules pluralRules) categories() []PluralCategory {
+	res := make([]PluralCategory, 0, len(rules)+1)
+	for i := range rules {
+		res = append(res, rules[i].category)
+	}
+	return append(res, PluralOther)
+}
+
+// parsePluralRules parses rules in CLDR syntax like
+// "one: i = 1 and v = 0; few: i = 2..4 and v = 0; many: v != 0".
+// Samples (@integer, @decimal) are ignored.
+func parsePluralRules(src string) (pluralRules, error) {
+	var res pluralRules
+
+	for _, part := range strings.Split(src, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, cond, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("plural rule %q: missing category", part)
+		}
+
+		cat, ok := ParsePluralCategory(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("plural rule %q: unknown category", part)
+		}
+
+		if pos := strings.IndexByte(cond, '@'); pos != -1 {
+			cond = cond[:pos]
+		}
+
+		c, err := parsePluralCondition(cond)
+		if err != nil {
+			return nil, fmt.Errorf("plural rule %q: %w", part, err)
+		}
+		if cat != PluralOther {
+			res = append(res, pluralRule{category: cat, condition: c})
+		}
+	}
+	return res, nil
+}
+
+func parsePluralCondition(src string) (pluralCondition, error) {
+	var res pluralCondition
+
+	for _, or := range strings.Split(src, " or ") {
+		var and []pluralRelation
+		for _, rel := range strings.Split(or, " and ") {
+			r, err := parsePluralRelation(strings.TrimSpace(rel))
+			if err != nil {
+				return nil, err
+			}
+			and = append(and, r)
+		}
+		res = append(res, and)
+	}
+	return res, nil
+}
+
+// parsePluralRelation parses "n % 10 = 2..4,9" or "v != 0".
+func parsePluralRelation(src string) (pluralRelation, error) {
+	var res pluralRelation
+
+	op := " = "
+	pos := strings.Index(src, " != ")
+	if pos != -1 {
+		op = " != "
+		res.negate = true
+	} else if pos = strings.Index(src, " = "); pos == -1 {
+		return res, fmt.Errorf("invalid relation %q", src)
+	}
+
+	expr := strings.Fields(src[:pos])
+	if len(expr) == 0 || len(expr[0]) != 1 || !strings.Contains("niwvftce", expr[0]) {
+		return res, fmt.Errorf("invalid operand in %q", src)
+	}
+	res.operand = expr[0][0]
+
+	switch len(expr) {
+	case 1:
+	case 3:
+		if expr[1] != "%" && expr[1] != "mod" {
+			return res, fmt.Errorf("invalid operator in %q", src)
+		}
+		m, err := strconv.ParseFloat(expr[2], 64)
+		if err != nil || m <= 0 {
+			return res, fmt.Errorf("invalid modulus in %q", src)
+		}
+		res.mod = m
+	default:
+		return res, fmt.Errorf("invalid expression in %q", src)
+	}
+
+	for _, rg := range strings.Split(strings.TrimSpace(src[pos+len(op):]), ",") {
+		from, to, isRange := strings.Cut(strings.TrimSpace(rg), "..")
+		if !isRange {
+			to = from
+		}
+		f, err1 := strconv.ParseFloat(from, 64)
+		t, err2 := strconv.ParseFloat(to, 64)
+		if err1 != nil || err2 != nil || f > t {
+			return res, fmt.Errorf("invalid range %q in %q", rg, src)
+		}
+		res.ranges = append(res.ranges, pluralRange{from: f, to: t})
+	}
+	return res, nil
+}
@@ -0,0 +1,246 @@
+package i18n
+
+import (
+	"testing"
+)
+
+func TestCardinal(t *testing.T) {
+
+	tests := []struct {
+		code     string
+		n        any
+		expected PluralCategory
+	}{
+		{"en", 1, PluralOne},
+		{"en", 0, PluralOther},
+		{"en", "1.0", PluralOther},
+		{"en", 2.5, PluralOther},
+		{"en-GB", 1, PluralOne},
+		{"cs", 1, PluralOne},
+		{"cs", 3, PluralFew},
+		{"cs", 5, PluralOther},
+		{"cs", 1.5, PluralMany},
+		{"ru", 21, PluralOne},
+		{"ru", 22, PluralFew},
+		{"ru", 11, PluralMany},
+		{"ru", 25, PluralMany},
+		{"ru", "1.5", PluralOther},
+		{"pl", 1, PluralOne},
+		{"pl", 22, PluralFew},
+		{"pl", 12, PluralMany},
+		{"pl", 21, PluralMany},
+		{"ar", 0, PluralZero},
+		{"ar", 2, PluralTwo},
+		{"ar", 103, PluralFew},
+		{"ar", 111, PluralMany},
+		{"ar", 100, PluralOther},
+		{"fr", 0, PluralOne},
+		{"fr", 1.5, PluralOne},
+		{"fr", 1000000, PluralMany},
+		{"fr", "1c6", PluralMany},
+		{"pt", 0, PluralOne},
+		{"pt-BR", 0, PluralOne},
+		{"pt-PT", 0, PluralOther},
+		{"lv", 10, PluralZero},
+		{"lv", 21, PluralOne},
+		{"lv", "0.1", PluralOne},
+		{"sr-Latn", 21, PluralOne},
+		{"hr", "1.2", PluralFew},
+		{"ja", 1, PluralOther},
+		{"xx", 1, PluralOther},
+		{"en", -1, PluralOne},
+		{"en", uint8(1), PluralOne},
+		{"en", "garbage", PluralOther},
+		{"en", struct{}{}, PluralOther},
+	}
+
+	r := NewLanguageRegistry()
+	for _, tt := range tests {
+		if got := r.Cardinal(r.Parse(tt.code), tt.n); got != tt.expected {
+			t.Errorf("%s %v: expected %s, got %s", tt.code, tt.n, tt.expected, got)
+		}
+	}
+}
+
+func TestParsePluralOperands(t *testing.T) {
+
+	tests := []struct {
+		src      string
+		expected pluralOperands
+	}{
+		{"1", pluralOperands{n: 1, i: 1}},
+		{"1.50", pluralOperands{n: 1.5, i: 1, v: 2, w: 1, f: 50, t: 5}},
+		{"-12.034", pluralOperands{n: 12.034, i: 12, v: 3, w: 3, f: 34, t: 34}},
+		{"1.2c3", pluralOperands{n: 1200, i: 1200, e: 3}},
+	}
+
+	for _, tt := range tests {
+		got, err := parsePluralOperands(tt.src)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.src, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("%s: expected %+v, got %+v", tt.src, tt.expected, got)
+		}
+	}
+
+	for _, src := range []string{"", ".5", "1.x", "1e"} {
+		if _, err := parsePluralOperands(src); err == nil {
+			t.Errorf("%q: expected error, got nil", src)
+		}
+	}
+}
+
+func TestParsePluralRules_Invalid(t *testing.T) {
+	for _, src := range []string{
+		"one",
+		"single: n = 1",
+		"one: n 1",
+		"one: x = 1",
+		"one: n % 0 = 1",
+		"one: n = 2..1",
+		"one: n % 10 % 2 = 1",
+	} {
+		if _, err := parsePluralRules(src); err == nil {
+			t.Errorf("%q: expected error, got nil", src)
+		}
+	}
+}
+
+func TestTranslationRequest_Plural(t *testing.T) {
+	r := NewLanguageRegistry()
+	en := r.Parse("en")
+	cs := r.Parse("cs")
+	sk := r.Parse("sk")
+	if err := r.SetFallback(sk, cs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tc := NewContainer(
+		WithLanguageRegistry(r),
+		WithPrimaryLanguage(en),
+		WithStorage(mapStorage{
+			"en.t18n": "Files[one]=# file\nFiles[other]=# files // number of files\nRank=Rank\n",
+			"cs.t18n": "Files[one]=# soubor\nFiles[few]=# soubory\nFiles[other]=# souborů\n",
+			"sk.t18n": "Files[one]=# súbor\n",
+		}),
+	)
+	if err := tc.ReadRegisteredFiles(); err != nil {
+		t.Fatalf("ReadRegisteredFiles failed: %v", err)
+	}
+
+	tests := []struct {
+		lang     Language
+		n        any
+		expected string
+	}{
+		{en, 1, "1 file"},
+		{en, 5, "5 files"},
+		{cs, 1, "1 soubor"},
+		{cs, 3, "3 soubory"},
+		{cs, 5, "5 souborů"},
+		{cs, 1.5, "1.5 souborů"},
+		{sk, 1, "1 súbor"},
+		// sk has no "few" and "other", cs is the next in the chain.
+		{sk, 3, "3 soubory"},
+		{r.Parse("de"), 2, "2 files"},
+	}
+
+	for _, tt := range tests {
+		if got := tc.Lang(tt.lang).Plural("Files", tt.n); got != tt.expected {
+			t.Errorf("%s %v: expected '%s', got '%s'", r.Code(tt.lang), tt.n, tt.expected, got)
+		}
+	}
+
+	if got := tc.Lang(en).Value("Files"); got != "# files" {
+		t.Errorf("expected '# files', got '%s'", got)
+	}
+	if got := tc.Lang(en).Hint("Files"); got != "number of files" {
+		t.Errorf("expected 'number of files', got '%s'", got)
+	}
+	if got := tc.Lang(cs).Plural("Rank", 2); got != "Rank" {
+		t.Errorf("expected 'Rank', got '%s'", got)
+	}
+	if got := tc.Lang(cs).Plural("Missing", 2); got != "Missing" {
+		t.Errorf("expected 'Missing', got '%s'", got)
+	}
+}
+
+func TestOrdinal(t *testing.T) {
+
+	tests := []struct {
+		code     string
+		n        any
+		expected PluralCategory
+	}{
+		{"en", 1, PluralOne},
+		{"en", 2, PluralTwo},
+		{"en", 3, PluralFew},
+		{"en", 4, PluralOther},
+		{"en", 11, PluralOther},
+		{"en", 12, PluralOther},
+		{"en", 21, PluralOne},
+		{"en", 102, PluralTwo},
+		{"en-US", 113, PluralOther},
+		{"fr", 1, PluralOne},
+		{"fr", 2, PluralOther},
+		{"it", 8, PluralMany},
+		{"it", 800, PluralMany},
+		{"sv", 22, PluralOne},
+		{"cy", 0, PluralZero},
+		{"cy", 5, PluralMany},
+		{"az", 100, PluralFew},
+		{"cs", 1, PluralOther},
+	}
+
+	r := NewLanguageRegistry()
+	for _, tt := range tests {
+		if got := r.Ordinal(r.Parse(tt.code), tt.n); got != tt.expected {
+			t.Errorf("%s %v: expected %s, got %s", tt.code, tt.n, tt.expected, got)
+		}
+	}
+}
+
+func TestTranslationRequest_Ordinal(t *testing.T) {
+	r := NewLanguageRegistry()
+	en := r.Parse("en")
+	de := r.Parse("de")
+	deAT := r.Parse("de-AT")
+
+	tc := NewContainer(
+		WithLanguageRegistry(r),
+		WithPrimaryLanguage(en),
+		WithStorage(mapStorage{
+			"en.t18n":    "Place[one]=#st\nPlace[two]=#nd\nPlace[few]=#rd\nPlace[other]=#th\n",
+			"de.t18n":    "Place[other]=#.\n",
+			"de-AT.t18n": "Place=Platz\n",
+			"fr.t18n":    "Place[one]=#er\nPlace[other]=#e\n",
+		}),
+	)
+	if err := tc.ReadRegisteredFiles(); err != nil {
+		t.Fatalf("ReadRegisteredFiles failed: %v", err)
+	}
+
+	tests := []struct {
+		lang     Language
+		n        int
+		expected string
+	}{
+		{en, 1, "1st"},
+		{en, 22, "22nd"},
+		{en, 13, "13th"},
+		{de, 3, "3."},
+		// de-AT has no ordinal forms, de is the next in the chain.
+		{deAT, 3, "3."},
+		{r.Lookup("fr"), 1, "1er"},
+		{r.Lookup("fr"), 2, "2e"},
+		{r.Parse("fr-CA"), 1, "1er"},
+	}
+
+	for _, tt := range tests {
+		if got := tc.Lang(tt.lang).Ordinal("Place", tt.n); got != tt.expected {
+			t.Errorf("%s %d: expected '%s', got '%s'", r.Code(tt.lang), tt.n, tt.expected, got)
+		}
+	}
+}
@@ -23,6 +23,7 @@ const emptyString = "#$%!@!@!@!@!"
 
 type StringOption func() string
 
+// WithDefault assigns a value returned if translation is not found.
 func WithDefault(s string) StringOption {
 	return func() string {
 		return s
@@ -30,55 +31,66 @@ func WithDefault(s string) StringOption {
 }
 
 // InLang returns string in language identified by code index.
+// The fallback chain is taken from the default registry.
 func (n String) InLang(li Language, opts ...StringOption) string {
+	return n.InLangIn(defaultRegistry, li, opts...)
+}
+
+// InLangIn returns string in language identified by code index.
+// The fallback chain is taken from the registry r.
+//
+// If no language of the chain is in the String, the chain of NoFoundIndex
+// is tried before the WithDefault value. If languages of the chain have
+// no value, the WithDefault value is returned before NoFoundIndex is tried.
+func (n String) InLangIn(r *LanguageRegistry, li Language, opts ...StringOption) string {
 
 	if len(n) == 0 || li < 0 {
 		return UnknownLanguageCode
 	}
 
-	for {
-		if int(li) >= len(n) {
-			li = NextLanguage(li)
-		} else {
-			break
+	inRange := false
+	for _, l := range r.chain(li) {
+		if int(l) >= len(n) {
+			continue
 		}
-	}
-
-	if li == Unknown {
-		if NoFoundIndex != Unknown {
-			li = NoFoundIndex
-		} else {
-			return UnknownLanguageCode
+		inRange = true
+		if n[l] != emptyString {
+			return n[l]
 		}
 	}
 
-	for {
-		res := n[li]
-		if res != emptyString {
-			return res
-		}
+	if !inRange && NoFoundIndex == Unknown {
+		return UnknownLanguageCode
+	}
 
-		li = NextLanguage(li)
-		if li != Unknown {
-			continue
-		}
+	if inRange && len(opts) > 0 {
+		return opts[0]()
+	}
 
-		if len(opts) > 0 {
-			return opts[0]()
+	if NoFoundIndex != Unknown {
+		for _, l := range r.chain(NoFoundIndex) {
+			if int(l) < len(n) && n[l] != emptyString {
+				return n[l]
+			}
 		}
+	}
 
-		if NoFoundIndex != Unknown {
-			li = NoFoundIndex
-			continue
-		}
-		break
+	if len(opts) > 0 {
+		return opts[0]()
 	}
 
 	return NoValue
 }
 
 // Bytes returns jsonb representation of the Name.
+// Language codes are taken from the default registry.
 func (n String) Bytes() []byte {
+	return n.BytesIn(defaultRegistry)
+}
+
+// BytesIn returns jsonb representation of the Name using language codes
+// of the registry r.
+func (n String) BytesIn(r *LanguageRegistry) []byte {
 	var buffer bytes.Buffer
 
 	buffer.WriteString("{")
@@ -89,7 +101,7 @@ func (n String) Bytes() []byte {
 		}
 		buffer.WriteString(sep)
 		buffer.WriteString(`"`)
-		buffer.WriteString(code(Language(li)))
+		buffer.WriteString(r.Code(Language(li)))
 		buffer.WriteString(`":"`)
 		buffer.WriteString(val)
 		buffer.WriteString(`"`)
@@ -126,7 +138,15 @@ func (n *String) Scan(value interface{}) error {
 }
 
 // ToString decodes jsonb like `{"en":"Name","cz":"Jméno","sr":"Име"}` into String type.
+// Language codes are registered in the default registry.
 func ToString(b []byte) (String, error) {
+	return defaultRegistry.ToString(b)
+}
+
+// ToString decodes jsonb like `{"en":"Name","cz":"Jméno","sr":"Име"}` into String type.
+// Language codes are registered in the registry r. Keys which are not
+// language tags are skipped, StringValidator rejects them.
+func (r *LanguageRegistry) ToString(b []byte) (String, error) {
 
 	var parsed map[string]string
 	err := json.Unmarshal(b, &parsed)
@@ -134,13 +154,17 @@ func ToString(b []byte) (String, error) {
 		return String{}, err
 	}
 
-	n := make(String, LastLanguage()+1)
+	n := make(String, r.LastLanguage()+1)
 	for i := 0; i < len(n); i++ {
 		n[i] = emptyString
 	}
 
 	for code, val := range parsed {
-		li := int(Parse(code))
+		l := r.Parse(code)
+		if l == Unknown {
+			continue
+		}
+		li := int(l)
 		if li >= len(n) {
 			x := li - len(n)
 			if x < 0 {
@@ -178,9 +202,16 @@ func (n *String) UnmarshalJSON(buf []byte) error {
 }
 
 // StringValidator returns function that validates jsonb data for String type.
+// Language codes are taken from the default registry.
 func StringValidator() func([]byte) bool {
+	return defaultRegistry.StringValidator()
+}
 
-	codes := LanguageCodes()
+// StringValidator returns function that validates jsonb data for String type.
+// Language codes are taken from the registry r.
+func (r *LanguageRegistry) StringValidator() func([]byte) bool {
+
+	codes := r.LanguageCodes()
 	validCodes := make(map[string]struct{}, len(codes))
 	for _, code := range codes {
 		validCodes[code] = struct{}{}
@@ -203,7 +234,13 @@ func StringValidator() func([]byte) bool {
 		for key, value := range data {
 			// check if key is one of language codes.
 			if _, ok := validCodes[key]; !ok {
-				return false
+				canonical, err := CanonicalCode(key)
+				if err != nil {
+					return false
+				}
+				if _, ok := validCodes[canonical]; !ok {
+					return false
+				}
 			}
 
 			if _, ok := value.(string); !ok {
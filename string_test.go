@@ -6,58 +6,79 @@ import (
 )
 
 func TestString(t *testing.T) {
-	resetLangState()
-
-	en := Parse("en")
-	fr := Parse("fr")
-	es := Parse("es")
+	r := NewLanguageRegistry()
+	en := r.Parse("en")
+	fr := r.Parse("fr")
+	es := r.Parse("es")
 
 	data := []byte(`{"en":"Hello","fr":"Bonjour"}`)
-	n, err := ToString(data)
+	n, err := r.ToString(data)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
 	t.Run("InLang", func(t *testing.T) {
 
-		if got := n.InLang(es, WithDefault("Hola")); got != "Hola" {
+		if got := n.InLangIn(r, es, WithDefault("Hola")); got != "Hola" {
 			t.Errorf("expected '%s', got '%s'", "Hola", got)
 		}
 
-		if got := n.InLang(en); got != "Hello" {
+		if got := n.InLangIn(r, en); got != "Hello" {
 			t.Errorf("expected 'Hello', got '%s'", got)
 		}
 
-		if got := n.InLang(fr); got != "Bonjour" {
+		if got := n.InLangIn(r, fr); got != "Bonjour" {
 			t.Errorf("expected 'Bonjour', got '%s'", got)
 		}
 
-		if got := n.InLang(Unknown); got != UnknownLanguageCode {
+		if got := n.InLangIn(r, Unknown); got != UnknownLanguageCode {
 			t.Errorf("expected '%s', got '%s'", UnknownLanguageCode, got)
 		}
 
-		if got := n.InLang(100); got != UnknownLanguageCode {
+		if got := n.InLangIn(r, 100); got != UnknownLanguageCode {
 			t.Errorf("expected '%s', got '%s'", UnknownLanguageCode, got)
 		}
 
-		if got := n.InLang(100, WithDefault("Hi")); got != UnknownLanguageCode {
+		if got := n.InLangIn(r, 100, WithDefault("Hi")); got != UnknownLanguageCode {
 			t.Errorf("expected '%s', got '%s'", UnknownLanguageCode, got)
 		}
 
-		if got := n.InLang(Parse("en-US")); got != "Hello" {
+		if got := n.InLangIn(r, r.Parse("en-US")); got != "Hello" {
 			t.Errorf("expected '%s', got '%s'", "Hello", got)
 		}
 
 	})
 
+	t.Run("NoFoundIndex", func(t *testing.T) {
+		defer func(li Language) { NoFoundIndex = li }(NoFoundIndex)
+		NoFoundIndex = en
+
+		// a language out of range tries NoFoundIndex before the default.
+		if got := n.InLangIn(r, 100, WithDefault("Hi")); got != "Hello" {
+			t.Errorf("expected 'Hello', got '%s'", got)
+		}
+		// a language without value returns the default first.
+		if got := n.InLangIn(r, es, WithDefault("Hola")); got != "Hola" {
+			t.Errorf("expected 'Hola', got '%s'", got)
+		}
+		if got := n.InLangIn(r, es); got != "Hello" {
+			t.Errorf("expected 'Hello', got '%s'", got)
+		}
+	})
+
 	t.Run("Bytes", func(t *testing.T) {
 		expected := string(data)
-		if got := string(n.Bytes()); got != expected {
+		if got := string(n.BytesIn(r)); got != expected {
 			t.Errorf("expected '%s', got '%s'", expected, got)
 		}
 	})
 
 	t.Run("Value", func(t *testing.T) {
+		// database/sql interfaces are bound to the default registry.
+		n, err := ToString(data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 		expected := string(data)
 		if got, err := n.Value(); err != nil {
 			t.Fatalf("unexpected error: %v", err)
@@ -71,13 +92,13 @@ func TestString(t *testing.T) {
 		if err := n.Scan(data); err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
-		if got := n.InLang(fr); got != "Bonjour" {
+		if got := n.InLang(Lookup("fr")); got != "Bonjour" {
 			t.Errorf("expected 'Bonjour', got '%s'", got)
 		}
 	})
 
 	t.Run("StringValidator", func(t *testing.T) {
-		validator := StringValidator()
+		validator := r.StringValidator()
 		if !validator(data) {
 			t.Errorf("expected valid JSON to pass validation")
 		}
@@ -88,7 +109,20 @@ func TestString(t *testing.T) {
 	})
 
 	t.Run("ToString", func(t *testing.T) {
-
+		// stored data with non-canonical and invalid keys is decoded.
+		n, err := r.ToString([]byte(`{"en":"Hello","EN-us":"Howdy","not a tag!":"x"}`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := n.InLangIn(r, r.Parse("en-US")); got != "Howdy" {
+			t.Errorf("expected 'Howdy', got '%s'", got)
+		}
+		if got := n.InLangIn(r, en); got != "Hello" {
+			t.Errorf("expected 'Hello', got '%s'", got)
+		}
+		if r.StringValidator()([]byte(`{"en":"Hello","not a tag!":"x"}`)) {
+			t.Errorf("expected invalid keys to fail validation")
+		}
 	})
 
 }
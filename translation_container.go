@@ -1,7 +1,13 @@
 package i18n
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 var NotFoundMarker = "\u2638"
@@ -33,26 +39,140 @@ type Item struct {
 	Key   string
 	Value string
 	Hint  string
+
+	// Variants holds variants of the value by name, like plural
+	// categories: {"one": "# file", "other": "# files"}, or genders
+	// for TranslationRequest.Select. It is a pointer, thus Item stays
+	// comparable; items are equal only if they share variants.
+	Variants *Variants
+
+	// Context disambiguates items having the same key, like "button"
+	// and "status" for the key "Open".
+	Context string
+}
+
+// Variants maps variant names to values of an Item.
+type Variants map[string]string
+
+// Variant returns the variant of the value by name.
+func (item *Item) Variant(name string) (string, bool) {
+	s, ok := item.variants()[name]
+	return s, ok
+}
+
+// SetVariant sets the variant of the value by name.
+func (item *Item) SetVariant(name, value string) {
+	if item.Variants == nil {
+		item.Variants = &Variants{}
+	}
+	(*item.Variants)[name] = value
+}
+
+// variants returns the variants of the item, nil if it has none.
+func (item *Item) variants() Variants {
+	if item.Variants == nil {
+		return nil
+	}
+	return *item.Variants
+}
+
+// id returns the key the item is indexed by in a Set.
+func (item *Item) id() string {
+	return itemID(item.Context, item.Key)
+}
+
+// displayID returns the item id as it is written in a .t18n file:
+// "Open@button".
+func displayID(id string) string {
+	if context, key, ok := strings.Cut(id, "\x04"); ok {
+		return key + ContextSeparator + context
+	}
+	return id
+}
+
+// itemID returns the index key of the key in the context.
+// Like in gettext, the context and the key are separated by EOT.
+func itemID(context, key string) string {
+	if context == "" {
+		return key
+	}
+	return context + "\x04" + key
 }
 
 // ResponseItem represents a row to be returned to the client.
 type ResponseItem struct {
 	Value string `json:"v"`
 	Hint  string `json:"h,omitempty"`
+
+	// Inherited is true if the value is taken from a fallback language.
+	// Set only if WithInheritedMark option is used.
+	Inherited bool `json:"i,omitempty"`
 }
 
 // Set holds a set of items.
 type Set struct {
 	items []Item
-	index map[string]int // key -> index in items
+	index map[string]int // item id -> index in items
 }
 
 // TranslationContainer is a store of all translated resource items.
+// The zero value has no translations and answers lookups with the
+// not-found strategy using the default registry, use NewContainer to
+// load files.
 type TranslationContainer struct {
-	cfg          containerConfig
+	cfg        containerConfig
+	customDirs []string
+
+	// snap holds loaded translations. It's replaced as a whole by Reload.
+	snap atomic.Pointer[snapshot]
+
+	// reloadMux serializes reloads.
+	reloadMux sync.Mutex
+
+	// metrics counts lookups, nil if WithMetrics is not used.
+	metrics *lookupMetrics
+}
+
+// snapshot is an immutable state of loaded translations.
+type snapshot struct {
 	translations map[key]Set
 	files        []file
-	customDirs   []string
+
+	// namespaces holds resolved namespace chains, see WithNamespaceParent.
+	namespaces map[string][]string
+
+	// loadedNamespaces holds namespaces having at least one file.
+	loadedNamespaces map[string]bool
+
+	// languages holds languages having translations in any namespace.
+	languages map[Language]bool
+
+	// generation is incremented by every successful reload.
+	generation uint64
+
+	// templates caches parsed values used by Format: *Item -> *templateEntry.
+	templates sync.Map
+
+	// messages caches compiled values used by Message: *Item -> message.
+	messages sync.Map
+
+	// warnings holds diagnostics of loaded files.
+	warnings Diagnostics
+
+	// chains holds fallback chains of the registry resolved at build time.
+	// It's replaced if the registry changes them, see SetFallback.
+	chains atomic.Pointer[fallbackChains]
+}
+
+// chain returns the fallback chain starting with li. Chains are taken
+// from the registry only if it has changed them since the last call.
+func (s *snapshot) chain(r *LanguageRegistry, li Language) []Language {
+	fc := s.chains.Load()
+	if fc == nil || fc.generation != r.generation.Load() {
+		fc = r.fallbackChains()
+		s.chains.Store(fc)
+	}
+	return fc.chain(li)
 }
 
 // FileStorager is an interface wrapping the methods for reading files.
@@ -83,6 +203,16 @@ type FileContentParser interface {
 	ParseFileContent(data []byte) ([]Item, error)
 }
 
+// DiagnosticParser is a FileContentParser reporting problems of the content.
+//
+// ParseFileDiagnostics returns items and problems found in the content.
+// The container fills Diagnostic.File, fails loading in the strict mode
+// and keeps diagnostics as warnings otherwise, see WithStrictParsing.
+type DiagnosticParser interface {
+	FileContentParser
+	ParseFileDiagnostics(data []byte) ([]Item, Diagnostics, error)
+}
+
 // containerConfig defines options for Container.
 type containerConfig struct {
 
@@ -103,6 +233,29 @@ type containerConfig struct {
 	filenameParser FilenameParser
 
 	parser FileContentParser
+
+	// registry holds languages the container is bound to.
+	registry *LanguageRegistry
+
+	// matchDefault is returned by Match if nothing matched.
+	// Primary language is used if it's Unknown.
+	matchDefault Language
+
+	// namespaceParents maps namespace to its parent namespace.
+	namespaceParents map[string]string
+
+	// messageFormat enables compilation of values as ICU MessageFormat
+	// patterns on load.
+	messageFormat bool
+
+	// missingHandler is called on keys not found in the requested language.
+	missingHandler MissingHandler
+
+	// metrics enables lookup counters.
+	metrics bool
+
+	// strictParsing fails loading on diagnostics of the parser.
+	strictParsing bool
 }
 
 type ContainerOption func(o *containerConfig)
@@ -114,6 +267,9 @@ func WithPrimaryLanguage(li Language) ContainerOption {
 	}
 }
 
+// WithFilenameParser assigns a parser of file names. The registry of
+// the container is assigned to a DefaultFilenameParser or
+// GettextFilenameParser without Registry.
 func WithFilenameParser(parser FilenameParser) ContainerOption {
 	return func(o *containerConfig) {
 		o.filenameParser = parser
@@ -159,104 +315,387 @@ func WithStrategy(strategy TranslationRequestStrategy) ContainerOption {
 	}
 }
 
+// WithLanguageRegistry binds the container to the language registry.
+// The default registry is used if the option is not provided.
+func WithLanguageRegistry(r *LanguageRegistry) ContainerOption {
+	return func(o *containerConfig) {
+		o.registry = r
+	}
+}
+
+// WithDefaultMatch assigns a language returned by Match if no language
+// of the Accept-Language header is available. The primary language is
+// used if the option is not provided.
+func WithDefaultMatch(li Language) ContainerOption {
+	return func(o *containerConfig) {
+		o.matchDefault = li
+	}
+}
+
+// WithNamespaceParent declares the parent of the namespace.
+// Lookup in the namespace falls through to the parent, then to the
+// parent of the parent and finally to the default namespace.
+//
+// Example:
+// WithNamespaceParent("customer1", "enterprise") gives customer1 -> enterprise -> ""
+//
+// Cycles are reported by ReadRegisteredFiles.
+func WithNamespaceParent(namespace, parent string) ContainerOption {
+	return func(o *containerConfig) {
+		if o.namespaceParents == nil {
+			o.namespaceParents = make(map[string]string)
+		}
+		o.namespaceParents[namespace] = parent
+	}
+}
+
+// WithMessageFormat compiles all values as ICU MessageFormat patterns
+// while files are loaded. Invalid patterns are reported by
+// ReadRegisteredFiles and Reload instead of TranslationRequest.Message.
+func WithMessageFormat() ContainerOption {
+	return func(o *containerConfig) {
+		o.messageFormat = true
+	}
+}
+
+// WithStrictParsing fails ReadRegisteredFiles and Reload with Diagnostics
+// of all files if the parser reports problems in any of them, like
+// malformed lines or duplicate keys. Without the option diagnostics are
+// kept as warnings, see TranslationContainer.Warnings. The parser shall implement
+// DiagnosticParser, DefaultParser does.
+func WithStrictParsing() ContainerOption {
+	return func(o *containerConfig) {
+		o.strictParsing = true
+	}
+}
+
+// WithMissingHandler assigns a handler called on every lookup of a key
+// not found in the requested language, see MissingHandler.
+func WithMissingHandler(h MissingHandler) ContainerOption {
+	return func(o *containerConfig) {
+		o.missingHandler = h
+	}
+}
+
+// ErrNamespaceCycle is returned if declared namespace parents form a cycle.
+var ErrNamespaceCycle = errors.New("namespace parent cycle")
+
 // NewContainer creates a new localization container.
-func NewContainer(opts ...func(o *containerConfig)) *TranslationContainer {
+func NewContainer(opts ...ContainerOption) *TranslationContainer {
 	tc := TranslationContainer{
-		translations: make(map[key]Set),
-		//lang:         GetSetLangIndex("?"),
 		cfg: containerConfig{
 			primaryLanguage: Unknown,
 			suffixPriority:  map[string]int{"": 0}, // file without suffix has the lowest priority.
 			storage:         &LocalFileStorage{},
 			parser:          &DefaultParser{},
 			strategy:        ReturnResourceCode,
-			filenameParser:  &DefaultFilenameParser{},
+			registry:        defaultRegistry,
+			matchDefault:    Unknown,
 		},
 	}
 
 	for _, opt := range opts {
 		opt(&tc.cfg)
 	}
+
+	// parsers without a registry parse languages into the registry
+	// of the container.
+	switch p := tc.cfg.filenameParser.(type) {
+	case nil:
+		tc.cfg.filenameParser = &DefaultFilenameParser{Registry: tc.cfg.registry}
+	case *DefaultFilenameParser:
+		if p.Registry == nil {
+			tc.cfg.filenameParser = &DefaultFilenameParser{Registry: tc.cfg.registry}
+		}
+	case DefaultFilenameParser:
+		if p.Registry == nil {
+			tc.cfg.filenameParser = &DefaultFilenameParser{Registry: tc.cfg.registry}
+		}
+	case *GettextFilenameParser:
+		if p.Registry == nil {
+			c := *p
+			c.Registry = tc.cfg.registry
+			tc.cfg.filenameParser = &c
+		}
+	case GettextFilenameParser:
+		if p.Registry == nil {
+			p.Registry = tc.cfg.registry
+			tc.cfg.filenameParser = &p
+		}
+	}
+
+	if tc.cfg.metrics {
+		tc.metrics = &lookupMetrics{}
+	}
+
+	tc.snap.Store(&snapshot{
+		translations: make(map[key]Set),
+	})
 	return &tc
 }
 
-// AddFiles registers full .t18n file names in the container.
-func (tc *TranslationContainer) addFiles(fullFileNames ...string) error {
+// Registry returns the language registry the container is bound to.
+func (tc *TranslationContainer) Registry() *LanguageRegistry {
+	if tc.cfg.registry == nil {
+		return defaultRegistry
+	}
+	return tc.cfg.registry
+}
+
+// parseFilenames parses full .t18n file names.
+func (tc *TranslationContainer) parseFilenames(fullFileNames ...string) ([]file, error) {
 
+	res := make([]file, 0, len(fullFileNames))
 	for _, ffn := range fullFileNames {
 		name, err := tc.cfg.filenameParser.ExtractFilename(ffn)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		pfi := file{name: name, fullName: ffn}
 		pfi.lang, pfi.namespace = tc.cfg.filenameParser.ParseFilename(name)
-		tc.files = append(tc.files, pfi)
+		res = append(res, pfi)
 	}
-	return nil
+	return res, nil
 }
 
-func (tc *TranslationContainer) sortFilesBySuffixPriority() {
-	sort.Slice(tc.files, func(i, j int) bool {
-		if tc.files[i].lang == tc.files[j].lang {
-			return tc.cfg.suffixPriority[tc.files[i].namespace] < tc.cfg.suffixPriority[tc.files[j].namespace]
+func (tc *TranslationContainer) sortFilesBySuffixPriority(files []file) {
+	sort.SliceStable(files, func(i, j int) bool {
+		if files[i].lang == files[j].lang {
+			return tc.cfg.suffixPriority[files[i].namespace] < tc.cfg.suffixPriority[files[j].namespace]
 		}
-		return tc.files[i].lang < tc.files[j].lang
+		return files[i].lang < files[j].lang
 	})
 }
 
 // ReadRegisteredFiles reads content of all registered files, parses and stores content
-// in the container.
+// in the container. It's equivalent of Reload(context.Background()).
 func (tc *TranslationContainer) ReadRegisteredFiles() error {
+	return tc.Reload(context.Background())
+}
+
+// Reload reads content of all files registered in the storage and
+// replaces loaded translations atomically. Lookups running concurrently
+// see either old or new translations, never a mix of them.
+//
+// If reading or parsing of any file fails, the error is returned and
+// previously loaded translations stay in use.
+func (tc *TranslationContainer) Reload(ctx context.Context) error {
+
+	tc.reloadMux.Lock()
+	defer tc.reloadMux.Unlock()
 
-	tc.addFiles(tc.cfg.storage.RegisteredFilenames()...)
+	snap, err := tc.buildSnapshot(ctx)
+	if err != nil {
+		return err
+	}
+
+	snap.generation = tc.snapshot().generation + 1
+	tc.snap.Store(snap)
+	return nil
+}
 
-	tc.sortFilesBySuffixPriority()
+// buildSnapshot reads registered files into a new snapshot.
+func (tc *TranslationContainer) buildSnapshot(ctx context.Context) (*snapshot, error) {
 
-	for _, f := range tc.files {
-		items, err := tc.loadFile(f.fullName)
+	namespaces, err := resolveNamespaces(tc.cfg.namespaceParents)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := tc.parseFilenames(tc.cfg.storage.RegisteredFilenames()...)
+	if err != nil {
+		return nil, err
+	}
+
+	tc.sortFilesBySuffixPriority(files)
+
+	snap := snapshot{
+		translations:     make(map[key]Set),
+		files:            files,
+		namespaces:       namespaces,
+		loadedNamespaces: make(map[string]bool),
+		languages:        make(map[Language]bool),
+	}
+	snap.chains.Store(tc.cfg.registry.fallbackChains())
+	compiled := make(map[key]map[string]message)
+
+	for _, f := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		items, diags, err := tc.loadFile(f)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		snap.warnings = append(snap.warnings, diags...)
+
+		if tc.cfg.messageFormat {
+			if err := compileMessages(compiled, f, items); err != nil {
+				return nil, err
+			}
+		}
+
+		snap.loadedNamespaces[f.namespace] = true
+		snap.languages[f.lang] = true
 
 		key := key{
 			lang:      f.lang,
 			namespace: f.namespace,
 		}
 
-		if ti, ok := tc.translations[key]; ok {
+		if ti, ok := snap.translations[key]; ok {
 			// replace
 			for j := range items {
-				if idx, ok := ti.index[items[j].Key]; ok {
+				if idx, ok := ti.index[items[j].id()]; ok {
 					ti.items[idx] = items[j]
 				} else {
 					ti.items = append(ti.items, items[j])
-					ti.index[items[j].Key] = len(ti.items) - 1
+					ti.index[items[j].id()] = len(ti.items) - 1
 				}
 			}
 			// important to assign back, because ti is a copy,
 			// and ti.items can refer to another address.
-			tc.translations[key] = ti
+			snap.translations[key] = ti
 		} else {
 			x := Set{index: make(map[string]int)}
 			x.items = items
-			for i, item := range items {
-				x.index[item.Key] = i
+			for i := range items {
+				x.index[items[i].id()] = i
+			}
+			snap.translations[key] = x
+		}
+	}
+
+	if tc.cfg.strictParsing && len(snap.warnings) > 0 {
+		return nil, snap.warnings
+	}
+
+	for k, ti := range snap.translations {
+		for i := range ti.items {
+			if m, ok := compiled[k][ti.items[i].id()]; ok {
+				snap.messages.Store(&ti.items[i], m)
 			}
-			tc.translations[key] = x
 		}
 	}
+	return &snap, nil
+}
+
+// compileMessages compiles values of the file items into compiled.
+func compileMessages(compiled map[key]map[string]message, f file, items []Item) error {
+	ms, ok := compiled[f.key]
+	if !ok {
+		ms = make(map[string]message, len(items))
+		compiled[f.key] = ms
+	}
+	for i := range items {
+		m, err := compileMessage(items[i].Value)
+		if err != nil {
+			return fmt.Errorf("%s: key %q: %w", f.name, items[i].Key, err)
+		}
+		ms[items[i].id()] = m
+	}
 	return nil
 }
 
-func (tc *TranslationContainer) loadFile(filename string) ([]Item, error) {
+// snapshot returns currently loaded translations.
+func (tc *TranslationContainer) snapshot() *snapshot {
+	if snap := tc.snap.Load(); snap != nil {
+		return snap
+	}
+	return &emptySnapshot
+}
+
+// emptySnapshot is used by the zero value container.
+var emptySnapshot snapshot
+
+// resolveNamespaces resolves namespace chains declared by WithNamespaceParent.
+func resolveNamespaces(parents map[string]string) (map[string][]string, error) {
+	res := make(map[string][]string, len(parents))
+	for ns := range parents {
+		chain := []string{ns}
+		seen := map[string]bool{ns: true}
+		for cur := ns; cur != ""; {
+			parent, ok := parents[cur]
+			if !ok {
+				parent = ""
+			}
+			if seen[parent] {
+				return nil, fmt.Errorf("%w: %q", ErrNamespaceCycle, ns)
+			}
+			seen[parent] = true
+			chain = append(chain, parent)
+			cur = parent
+		}
+		res[ns] = chain
+	}
+	return res, nil
+}
 
-	data, err := tc.cfg.storage.ReadFile(filename)
+// namespaceChain returns the namespace followed by its parents and
+// the default namespace.
+func (snap *snapshot) namespaceChain(ns string) []string {
+	if ns == "" {
+		return defaultNamespaceChain
+	}
+	if chain, ok := snap.namespaces[ns]; ok {
+		return chain
+	}
+	return []string{ns, ""}
+}
+
+var defaultNamespaceChain = []string{""}
+
+func (tc *TranslationContainer) loadFile(f file) ([]Item, Diagnostics, error) {
+
+	data, err := tc.cfg.storage.ReadFile(f.fullName)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	dp, ok := tc.cfg.parser.(DiagnosticParser)
+	if !ok {
+		items, err := tc.cfg.parser.ParseFileContent(data)
+		return items, nil, err
+	}
+
+	items, diags, err := dp.ParseFileDiagnostics(data)
+	if err != nil {
+		return nil, nil, err
 	}
+	for i := range diags {
+		diags[i].File = f.name
+	}
+	return items, diags, nil
+}
+
+// Warnings returns diagnostics of the loaded files, like malformed lines
+// and duplicate keys. It's empty in the strict mode, see WithStrictParsing.
+func (tc *TranslationContainer) Warnings() Diagnostics {
+	return tc.snapshot().warnings
+}
+
+// Match returns the best language for the value of the Accept-Language
+// header for which the container has translations. Returns the default
+// language (see WithDefaultMatch) if nothing matched.
+func (tc *TranslationContainer) Match(header string) Language {
+	li, _ := tc.MatchConfidence(header)
+	return li
+}
+
+// MatchConfidence is like Match but returns the confidence of the match as well.
+func (tc *TranslationContainer) MatchConfidence(header string) (Language, Confidence) {
+	def := tc.cfg.matchDefault
+	if def == Unknown {
+		def = tc.cfg.primaryLanguage
+	}
+	return NewMatcher(tc.Registry(), tc.hasLanguage, def).Match(header)
+}
 
-	return tc.cfg.parser.ParseFileContent(data)
+// hasLanguage returns true if the container has translations in the language li.
+func (tc *TranslationContainer) hasLanguage(li Language) bool {
+	return tc.snapshot().languages[li]
 }
 
 // genKey generates resource key for JSON response.
@@ -0,0 +1,114 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+// failingStorage fails reading files after fail is set.
+type failingStorage struct {
+	mapStorage
+	fail bool
+}
+
+func (s *failingStorage) ReadFile(name string) ([]byte, error) {
+	if s.fail {
+		return nil, errors.New("storage is not available")
+	}
+	return s.mapStorage.ReadFile(name)
+}
+
+func TestContainer_Reload(t *testing.T) {
+	r := NewLanguageRegistry()
+	en := r.Parse("en")
+
+	storage := &failingStorage{mapStorage: mapStorage{"en.t18n": "Save=Save\n"}}
+	tc := NewContainer(WithLanguageRegistry(r), WithStorage(storage))
+
+	if err := tc.ReadRegisteredFiles(); err != nil {
+		t.Fatalf("ReadRegisteredFiles failed: %v", err)
+	}
+	if err := tc.ReadRegisteredFiles(); err != nil {
+		t.Fatalf("ReadRegisteredFiles failed: %v", err)
+	}
+	if n := len(tc.snapshot().files); n != 1 {
+		t.Errorf("expected 1 file, got %d", n)
+	}
+
+	storage.mapStorage["en.t18n"] = "Save=Store\n"
+	if err := tc.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if got := tc.Lang(en).Value("Save"); got != "Store" {
+		t.Errorf("expected 'Store', got '%s'", got)
+	}
+
+	storage.fail = true
+	storage.mapStorage["en.t18n"] = "Save=Keep\n"
+	if err := tc.Reload(context.Background()); err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+	if got := tc.Lang(en).Value("Save"); got != "Store" {
+		t.Errorf("expected old snapshot 'Store', got '%s'", got)
+	}
+
+	storage.fail = false
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := tc.Reload(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if gen := tc.snapshot().generation; gen != 3 {
+		t.Errorf("expected generation 3, got %d", gen)
+	}
+}
+
+func TestContainer_ReloadConcurrent(t *testing.T) {
+	r := NewLanguageRegistry()
+	en := r.Parse("en")
+	enGB := r.Parse("en-GB")
+
+	storage := mapStorage{
+		"en.t18n":    "Save=Save\nExit=Exit\n",
+		"en-GB.t18n": "Lift=Lift\n",
+	}
+	tc := NewContainer(WithLanguageRegistry(r), WithPrimaryLanguage(en), WithStorage(storage))
+	if err := tc.ReadRegisteredFiles(); err != nil {
+		t.Fatalf("ReadRegisteredFiles failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tr := tc.Lang(enGB)
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				if got := tr.Value("Save"); got != "Save" {
+					t.Errorf("expected 'Save', got '%s'", got)
+					return
+				}
+				if _, err := tr.JSON(); err != nil {
+					t.Errorf("unexpected error: %v", err)
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 50; i++ {
+		if err := tc.Reload(context.Background()); err != nil {
+			t.Errorf("Reload failed: %v", err)
+		}
+	}
+	close(stop)
+	wg.Wait()
+}
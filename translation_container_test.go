@@ -0,0 +1,421 @@
+package i18n
+
+import (
+	"errors"
+	"io/fs"
+	"sort"
+	"testing"
+	"time"
+)
+
+// newTestContainer returns a container bound to the registry r with
+// ./testdata files loaded.
+func newTestContainer(t *testing.T, r *LanguageRegistry, opts ...ContainerOption) *TranslationContainer {
+	t.Helper()
+
+	fs := NewLocalFileStorage()
+	if err := fs.RegisterFiles("*.t18n", "./testdata"); err != nil {
+		t.Fatalf("RegisterFiles failed: %v", err)
+	}
+
+	opts = append([]ContainerOption{WithLanguageRegistry(r), WithStorage(fs)}, opts...)
+	tc := NewContainer(opts...)
+	if err := tc.ReadRegisteredFiles(); err != nil {
+		t.Fatalf("ReadRegisteredFiles failed: %v", err)
+	}
+	return tc
+}
+
+func TestContainer_LanguageRegistry(t *testing.T) {
+	r := NewLanguageRegistry()
+	tc := newTestContainer(t, r)
+
+	if tc.Registry() != r {
+		t.Fatalf("container is not bound to the registry")
+	}
+
+	enGB := r.Lookup("en-GB")
+	if enGB == Unknown {
+		t.Fatalf("expected en-GB registered in the container registry")
+	}
+
+	if got := tc.Lang(enGB).Value("Save"); got != "Save" {
+		t.Errorf("expected 'Save', got '%s'", got)
+	}
+	if got := tc.Lang(enGB).Value("Lift"); got != "Elevator" {
+		t.Errorf("expected 'Elevator', got '%s'", got)
+	}
+}
+
+// mapStorage is an in-memory FileStorager.
+type mapStorage map[string]string
+
+func (s mapStorage) RegisteredFilenames() []string {
+	res := make([]string, 0, len(s))
+	for name := range s {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func (s mapStorage) ReadFile(name string) ([]byte, error) {
+	data, ok := s[name]
+	if !ok {
+		return nil, fs.ErrNotExist
+	}
+	return []byte(data), nil
+}
+
+func TestContainer_FallbackChain(t *testing.T) {
+	r := NewLanguageRegistry()
+	tc := NewContainer(
+		WithLanguageRegistry(r),
+		WithPrimaryLanguage(r.Parse("en")),
+		WithStorage(mapStorage{
+			"en.t18n":    "Save=Save\nCancel=Cancel\nExit=Exit\n",
+			"pt.t18n":    "Save=Guardar\nCancel=Cancelar\n",
+			"pt-PT.t18n": "Save=Gravar\n",
+			"pt-BR.t18n": "",
+		}),
+	)
+	if err := tc.ReadRegisteredFiles(); err != nil {
+		t.Fatalf("ReadRegisteredFiles failed: %v", err)
+	}
+
+	ptBR := r.Lookup("pt-BR")
+	if got := tc.Lang(ptBR).Value("Save"); got != "Guardar" {
+		t.Errorf("expected 'Guardar', got '%s'", got)
+	}
+
+	if err := r.SetFallbackCodes("pt-BR", "pt-PT"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tr := tc.Lang(ptBR)
+	for k, v := range map[string]string{"Save": "Gravar", "Cancel": "Cancelar", "Exit": "Exit"} {
+		if got := tr.Value(k); got != v {
+			t.Errorf("%s: expected '%s', got '%s'", k, v, got)
+		}
+	}
+
+	// lookups read chains from the snapshot and don't wait for the registry.
+	r.mux.Lock()
+	done := make(chan string, 1)
+	go func() { done <- tr.Value("Cancel") }()
+	select {
+	case got := <-done:
+		if got != "Cancelar" {
+			t.Errorf("expected 'Cancelar', got '%s'", got)
+		}
+	case <-time.After(time.Second):
+		t.Error("lookup is blocked by the registry lock")
+	}
+	r.mux.Unlock()
+
+	buf, err := tr.JSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `{"Cancel":{"v":"Cancelar"},"Exit":{"v":"Exit"},"Save":{"v":"Gravar"}}`
+	if string(buf) != expected {
+		t.Errorf("expected %s, got %s", expected, buf)
+	}
+
+	n, err := r.ToString([]byte(`{"pt-PT":"Nome","en":"Name"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := n.InLangIn(r, ptBR); got != "Nome" {
+		t.Errorf("expected 'Nome', got '%s'", got)
+	}
+}
+
+func TestTranslationRequest_JSON(t *testing.T) {
+	r := NewLanguageRegistry()
+	tc := newTestContainer(t, r, WithPrimaryLanguage(r.Parse("de")))
+
+	enGB := r.Lookup("en-GB")
+	enUS := r.Lookup("en-US")
+
+	t.Run("Chain", func(t *testing.T) {
+		buf, err := tc.Lang(enGB).JSON()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// en-GB -> en -> de
+		expected := `{"Cancel":{"v":"Cancel"},"Delete":{"v":"Delete"},"Exit":{"v":"Sign out"},"Lift":{"v":"Elevator"},"Save":{"v":"Save"}}`
+		if string(buf) != expected {
+			t.Errorf("expected %s, got %s", expected, buf)
+		}
+	})
+
+	t.Run("Namespace", func(t *testing.T) {
+		buf, err := tc.Namespace("customer1", enUS).JSON(WithInheritedMark())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := `{"Cancel":{"v":"Cancel","i":true},"Delete":{"v":"Delete","i":true},"Exit":{"v":"Sign out","i":true},"Lift":{"v":"Hoist"},"Save":{"v":"Save","i":true}}`
+		if string(buf) != expected {
+			t.Errorf("expected %s, got %s", expected, buf)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		r := NewLanguageRegistry()
+		tc := newTestContainer(t, r)
+		if _, err := tc.Lang(r.Parse("fr")).JSON(); err == nil {
+			t.Errorf("expected error, got nil")
+		}
+	})
+}
+
+func TestContainer_NamespaceParent(t *testing.T) {
+	r := NewLanguageRegistry()
+	en := r.Parse("en")
+	enUS := r.Parse("en-US")
+
+	tc := NewContainer(
+		WithLanguageRegistry(r),
+		WithPrimaryLanguage(en),
+		WithNamespaceParent("customer1", "enterprise"),
+		WithStorage(mapStorage{
+			"en.t18n":              "Save=Save\nCancel=Cancel\nExit=Exit\nLift=Lift\n",
+			"en.enterprise.t18n":   "Exit=Leave workspace\nCancel=Abort\n",
+			"en-US.t18n":           "Cancel=Cancel it\n",
+			"en-US.customer1.t18n": "Lift=Hoist\n",
+		}),
+	)
+	if err := tc.ReadRegisteredFiles(); err != nil {
+		t.Fatalf("ReadRegisteredFiles failed: %v", err)
+	}
+
+	tr := tc.Namespace("customer1", enUS)
+	expected := map[string]string{
+		"Lift":   "Hoist",           // en-US.customer1
+		"Exit":   "Leave workspace", // en.enterprise
+		"Cancel": "Abort",           // en.enterprise wins over en-US default namespace
+		"Save":   "Save",            // en
+	}
+	for k, v := range expected {
+		if got := tr.Value(k); got != v {
+			t.Errorf("%s: expected '%s', got '%s'", k, v, got)
+		}
+	}
+
+	// namespace without declared parent falls through to the default namespace.
+	if got := tc.Namespace("customer2", enUS).Value("Cancel"); got != "Cancel it" {
+		t.Errorf("expected 'Cancel it', got '%s'", got)
+	}
+
+	buf, err := tr.JSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s := `{"Cancel":{"v":"Abort"},"Exit":{"v":"Leave workspace"},"Lift":{"v":"Hoist"},"Save":{"v":"Save"}}`; string(buf) != s {
+		t.Errorf("expected %s, got %s", s, buf)
+	}
+}
+
+func TestContainer_NamespacePrimaryLast(t *testing.T) {
+	r := NewLanguageRegistry()
+	en, de := r.Parse("en"), r.Parse("de")
+
+	tc := NewContainer(
+		WithLanguageRegistry(r),
+		WithPrimaryLanguage(de),
+		WithStorage(mapStorage{
+			"de.t18n":           "Save=Speichern\nExit=Beenden\n",
+			"de.customer1.t18n": "Save=Speichern\n",
+			"en.t18n":           "Save=Save\n",
+		}),
+	)
+	if err := tc.ReadRegisteredFiles(); err != nil {
+		t.Fatalf("ReadRegisteredFiles failed: %v", err)
+	}
+
+	// the requested language in the default namespace wins over
+	// the primary language in the requested namespace
+	tr := tc.Namespace("customer1", en)
+	if got := tr.Value("Save"); got != "Save" {
+		t.Errorf("expected 'Save', got '%s'", got)
+	}
+	if got := tr.Value("Exit"); got != "Beenden" {
+		t.Errorf("expected 'Beenden', got '%s'", got)
+	}
+
+	buf, err := tr.JSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s := `{"Exit":{"v":"Beenden"},"Save":{"v":"Save"}}`; string(buf) != s {
+		t.Errorf("expected %s, got %s", s, buf)
+	}
+}
+
+func TestContainer_NamespaceCycle(t *testing.T) {
+	tc := NewContainer(
+		WithLanguageRegistry(NewLanguageRegistry()),
+		WithNamespaceParent("a", "b"),
+		WithNamespaceParent("b", "a"),
+		WithStorage(mapStorage{}),
+	)
+	if err := tc.ReadRegisteredFiles(); !errors.Is(err, ErrNamespaceCycle) {
+		t.Errorf("expected ErrNamespaceCycle, got %v", err)
+	}
+}
+
+func TestTranslationRequest_ValueCtx(t *testing.T) {
+	r := NewLanguageRegistry()
+	tc := NewContainer(
+		WithLanguageRegistry(r),
+		WithPrimaryLanguage(r.Parse("en")),
+		WithStorage(mapStorage{
+			"en.t18n":    "Open=Open\nOpen@status=Opened // badge\nFiles@toolbar[one]=# file\nFiles@toolbar[other]=# files\n",
+			"de.t18n":    "Open=Öffnen\nOpen@status=Geöffnet\n",
+			"de-AT.t18n": "Open@button=Aufmachen\n",
+			"cs.t18n":    "Open=Otevřít\n",
+		}),
+	)
+	if err := tc.ReadRegisteredFiles(); err != nil {
+		t.Fatalf("ReadRegisteredFiles failed: %v", err)
+	}
+
+	deAT := tc.Lang(r.Parse("de-AT"))
+	tests := []struct {
+		context  string
+		expected string
+	}{
+		{"", "Öffnen"},
+		{"button", "Aufmachen"},
+		{"status", "Geöffnet"},
+		{"menu", "Öffnen"},
+	}
+	for _, tt := range tests {
+		if got := deAT.ValueCtx(tt.context, "Open"); got != tt.expected {
+			t.Errorf("%q: expected %q, got %q", tt.context, tt.expected, got)
+		}
+	}
+	if got := deAT.Value("Open"); got != "Öffnen" {
+		t.Errorf("expected 'Öffnen', got %q", got)
+	}
+
+	// the requested language wins over the context in the primary language
+	if got := tc.Lang(r.Parse("cs")).ValueCtx("status", "Open"); got != "Otevřít" {
+		t.Errorf("expected 'Otevřít', got %q", got)
+	}
+
+	en := tc.Lang(r.Parse("en"))
+	if got := en.ValueCtx("toolbar", "Files"); got != "# files" {
+		t.Errorf("expected '# files', got %q", got)
+	}
+	if got := en.ValueCtx("toolbar", "Save"); got != "Save" {
+		t.Errorf("expected 'Save', got %q", got)
+	}
+
+	buf, err := en.JSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `{"Files@toolbar":{"v":"# files"},"Open":{"v":"Open"},"Open@status":{"v":"Opened","h":"badge"}}`
+	if string(buf) != expected {
+		t.Errorf("expected %s, got %s", expected, buf)
+	}
+}
+
+func TestTranslationRequest_Select(t *testing.T) {
+	r := NewLanguageRegistry()
+	tc := NewContainer(
+		WithLanguageRegistry(r),
+		WithPrimaryLanguage(r.Parse("en")),
+		WithStorage(mapStorage{
+			"en.t18n": "Welcome=Welcome\nInvited[female]=She invited you\nInvited[male]=He invited you\nInvited[other]=They invited you\n",
+			"cs.t18n": "Welcome[male]=Vítej, milý uživateli\nWelcome[female]=Vítej, milá uživatelko\nWelcome[other]=Vítejte\nInvited[female]=Pozvala vás\n",
+		}),
+	)
+	if err := tc.ReadRegisteredFiles(); err != nil {
+		t.Fatalf("ReadRegisteredFiles failed: %v", err)
+	}
+
+	cs := tc.Lang(r.Parse("cs"))
+	en := tc.Lang(r.Parse("en"))
+	tests := []struct {
+		tr       TranslationRequest
+		key      string
+		selector string
+		expected string
+	}{
+		{cs, "Welcome", "female", "Vítej, milá uživatelko"},
+		{cs, "Welcome", "neuter", "Vítejte"},
+		{cs, "Welcome", "", "Vítejte"},
+		{cs, "Invited", "female", "Pozvala vás"},
+		{cs, "Invited", "male", "He invited you"},
+		{en, "Welcome", "male", "Welcome"},
+		{en, "Missing", "male", "Missing"},
+	}
+	for _, tt := range tests {
+		if got := tt.tr.Select(tt.key, tt.selector); got != tt.expected {
+			t.Errorf("%s[%s]: expected %q, got %q", tt.key, tt.selector, tt.expected, got)
+		}
+	}
+	if got := cs.Value("Welcome"); got != "Vítejte" {
+		t.Errorf("expected 'Vítejte', got %q", got)
+	}
+}
+
+func TestItem_Comparable(t *testing.T) {
+	a := Item{Key: "Files", Value: "# files"}
+	a.SetVariant("one", "# file")
+
+	seen := map[Item]bool{a: true}
+	if !seen[a] {
+		t.Errorf("expected the item found by value")
+	}
+	if v, ok := a.Variant("one"); !ok || v != "# file" {
+		t.Errorf("expected variant '# file', got %q", v)
+	}
+	if _, ok := (&Item{}).Variant("one"); ok {
+		t.Errorf("expected no variant of an item without variants")
+	}
+}
+
+func TestContainer_FilenameParserRegistry(t *testing.T) {
+	r := NewLanguageRegistry()
+	tc := NewContainer(
+		WithLanguageRegistry(r),
+		WithStorage(mapStorage{"qaa.t18n": "Save=Uložit\n"}),
+		WithFilenameParser(&DefaultFilenameParser{}),
+	)
+	if err := tc.ReadRegisteredFiles(); err != nil {
+		t.Fatalf("ReadRegisteredFiles failed: %v", err)
+	}
+
+	qaa := r.Lookup("qaa")
+	if qaa == Unknown {
+		t.Fatalf("expected qaa registered in the container registry")
+	}
+	if defaultRegistry.Lookup("qaa") != Unknown {
+		t.Errorf("expected qaa not registered in the default registry")
+	}
+	if got := tc.Lang(qaa).Value("Save"); got != "Uložit" {
+		t.Errorf("expected 'Uložit', got '%s'", got)
+	}
+}
+
+func TestContainer_ZeroValue(t *testing.T) {
+	var tc TranslationContainer
+
+	tr := tc.Lang(0)
+	if got := tr.Value("Save"); got != "Save" {
+		t.Errorf("expected 'Save', got '%s'", got)
+	}
+	if got := tr.ValueCtx("button", "Open"); got != "Open" {
+		t.Errorf("expected 'Open', got '%s'", got)
+	}
+	if got := tc.Namespace("admin", 0).Hint("Save"); got != "" {
+		t.Errorf("expected empty hint, got '%s'", got)
+	}
+	if tc.Registry() != DefaultRegistry() {
+		t.Error("expected the default registry")
+	}
+}
@@ -0,0 +1,277 @@
+package i18n
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// Document is a .t18n file kept as lines, so it can be edited key by
+// key and written back with comments, blank lines, order and formatting
+// of untouched items preserved.
+//
+// Example:
+//
+//	doc, err := i18n.ParseDocument(data)
+//	item, _ := doc.Item("Save", "")
+//	item.Value = "Store"
+//	doc.Set(item)
+//	err = os.WriteFile(name, doc.Bytes(), 0o644)
+type Document struct {
+	lines []docLine
+
+	// eol is the line ending of the parsed file, finalEOL reports if
+	// the last line is terminated.
+	eol      string
+	finalEOL bool
+
+	// extended reports if the document uses the extended syntax.
+	extended bool
+}
+
+// docLine is a logical line with its original text.
+type docLine struct {
+	raw []string
+	logicalLine
+}
+
+// ParseDocument parses the content of a .t18n file. Malformed lines are
+// kept as is, see DefaultParser.ParseFileDiagnostics for their diagnostics.
+func ParseDocument(data []byte) (*Document, error) {
+	lines, err := readLines(data)
+	if err != nil {
+		return nil, err
+	}
+
+	d := Document{
+		eol:      "\n",
+		finalEOL: len(data) == 0 || data[len(data)-1] == '\n',
+	}
+	if bytes.Contains(data, []byte("\r\n")) {
+		d.eol = "\r\n"
+	}
+
+	d.extended = isExtended(lines)
+	for _, l := range scanLines(lines, d.extended) {
+		d.lines = append(d.lines, docLine{
+			raw:         lines[l.first : l.first+l.n],
+			logicalLine: l,
+		})
+	}
+	return &d, nil
+}
+
+// Bytes returns the content of the document.
+func (d *Document) Bytes() []byte {
+	var buf bytes.Buffer
+	_, _ = d.WriteTo(&buf)
+	return buf.Bytes()
+}
+
+// WriteTo writes the content of the document to w.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var raw []string
+	for i := range d.lines {
+		raw = append(raw, d.lines[i].raw...)
+	}
+
+	s := strings.Join(raw, d.eol)
+	if d.finalEOL && len(raw) > 0 {
+		s += d.eol
+	}
+	n, err := io.WriteString(w, s)
+	return int64(n), err
+}
+
+// Items returns items of the document like DefaultParser does.
+func (d *Document) Items() []Item {
+	p := DefaultParser{Extended: d.extended}
+	items, _, _ := p.ParseFileDiagnostics(d.Bytes())
+	return items
+}
+
+// Item returns the item of the key in the context.
+func (d *Document) Item(key, context string) (Item, bool) {
+	id := itemID(context, key)
+	for _, item := range d.Items() {
+		if item.id() == id {
+			return item, true
+		}
+	}
+	return Item{}, false
+}
+
+// entries returns indexes of lines declaring the key in the context.
+func (d *Document) entries(key, context string) []int {
+	var res []int
+	for i := range d.lines {
+		l := &d.lines[i]
+		if l.isEntry() && l.err == nil && l.entry.key == key && l.entry.context == context {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// Set adds or replaces the item. Lines of the item which values and
+// hints are not changed are kept as is, changed lines are replaced in
+// place, new variants follow existing lines of the item. A new item is
+// appended to the end of the document.
+//
+// If the item can't be written without the extended syntax, the document
+// is switched to it: ExtendedSyntaxHeader is inserted and lines which
+// would be read differently are rewritten. The context and variant names
+// are expected to be valid, see EncodeItems.
+func (d *Document) Set(item Item) {
+	if !d.extended {
+		for _, e := range itemEntries(&item) {
+			if !isPlainEntry(e) {
+				d.extend()
+				break
+			}
+		}
+	}
+
+	existing := d.entries(item.Key, item.Context)
+
+	old, found := d.Item(item.Key, item.Context)
+	wanted := itemEntries(&item)
+	if w := wanted[len(wanted)-1]; w.variant != "" {
+		// keep the plain value equal to the variant "other"
+		for _, idx := range existing {
+			if d.lines[idx].entry.variant == "" {
+				wanted = append(wanted, entry{key: item.Key, context: item.Context, value: item.Value})
+				break
+			}
+		}
+	}
+	if found && old.Hint == item.Hint {
+		// keep hints where they are
+		for i := range wanted {
+			wanted[i].hint = ""
+			for _, idx := range existing {
+				if e := d.lines[idx].entry; e.variant == wanted[i].variant {
+					wanted[i].hint = e.hint
+				}
+			}
+		}
+	}
+
+	byVariant := make(map[string]int) // variant -> index of the last line
+	for _, idx := range existing {
+		byVariant[d.lines[idx].entry.variant] = idx
+	}
+
+	insertAt := len(d.lines)
+	if len(existing) > 0 {
+		insertAt = existing[len(existing)-1] + 1
+	}
+
+	keep := make(map[int]bool)
+	var added []docLine
+	for _, e := range wanted {
+		idx, ok := byVariant[e.variant]
+		if !ok {
+			added = append(added, d.newLine(e))
+			continue
+		}
+		keep[idx] = true
+		if cur := d.lines[idx].entry; cur.value != e.value || cur.hint != e.hint {
+			d.lines[idx] = d.newLine(e)
+		}
+	}
+
+	lines := make([]docLine, 0, len(d.lines)+len(added))
+	for i := range d.lines {
+		if i == insertAt {
+			lines = append(lines, added...)
+		}
+		if isExisting(existing, i) && !keep[i] {
+			continue
+		}
+		lines = append(lines, d.lines[i])
+	}
+	if insertAt == len(d.lines) {
+		lines = append(lines, added...)
+	}
+	d.lines = lines
+}
+
+// Delete removes all lines of the key in the context. It returns false
+// if the document has no such key.
+func (d *Document) Delete(key, context string) bool {
+	existing := d.entries(key, context)
+	if len(existing) == 0 {
+		return false
+	}
+
+	lines := d.lines[:0]
+	for i := range d.lines {
+		if !isExisting(existing, i) {
+			lines = append(lines, d.lines[i])
+		}
+	}
+	d.lines = lines
+	return true
+}
+
+func isExisting(existing []int, i int) bool {
+	for _, idx := range existing {
+		if idx == i {
+			return true
+		}
+	}
+	return false
+}
+
+// extend switches the document to the extended syntax.
+func (d *Document) extend() {
+	lines := make([]docLine, 0, len(d.lines)+1)
+	lines = append(lines, docLine{
+		raw:         []string{ExtendedSyntaxHeader},
+		logicalLine: logicalLine{n: 1, text: ExtendedSyntaxHeader},
+	})
+
+	for _, l := range d.lines {
+		switch {
+		case !l.isEntry():
+		case l.err != nil:
+			// malformed lines are skipped, but they must not continue
+			// on the next line.
+			if hasContinuation(l.text) {
+				l.raw = []string{"# " + l.raw[0]}
+				l.text = l.raw[0]
+			}
+		default:
+			if x := scanLines(l.raw, true); len(x) != 1 || x[0].err != nil || !sameEntry(x[0].entry, l.entry) {
+				l = newDocLine(l.entry, true)
+			}
+		}
+		lines = append(lines, l)
+	}
+
+	d.lines = lines
+	d.extended = true
+}
+
+// sameEntry reports if the entries declare the same value and hint.
+func sameEntry(a, b entry) bool {
+	a.rawKey, b.rawKey = "", ""
+	return a == b
+}
+
+func (d *Document) newLine(e entry) docLine {
+	return newDocLine(e, d.extended)
+}
+
+func newDocLine(e entry, extended bool) docLine {
+	raw := encodeEntry(e, extended)
+	return docLine{
+		raw: raw,
+		logicalLine: logicalLine{
+			n:     len(raw),
+			text:  raw[0],
+			entry: e,
+		},
+	}
+}
@@ -3,60 +3,594 @@ package i18n
 import (
 	"bufio"
 	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
 	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// ErrMalformedLine is reported for a line which is not a comment
+	// and not an item.
+	ErrMalformedLine = errors.New("malformed line")
+
+	// ErrEmptyKey is reported for an item without key.
+	ErrEmptyKey = errors.New("empty key")
+
+	// ErrDuplicateKey is reported for a key declared twice in a file.
+	ErrDuplicateKey = errors.New("duplicate key")
+
+	// ErrInvalidUTF8 is reported for a line which is not valid UTF-8.
+	ErrInvalidUTF8 = errors.New("invalid UTF-8")
 )
 
 // HintSeparator holds a separator between value and hint in a .t18n file.
 var HintSeparator = "//"
 
-type DefaultParser struct{}
+// ContextSeparator holds a separator between key and context in a .t18n file.
+var ContextSeparator = "@"
+
+// ExtendedSyntaxHeader is the first line of .t18n files written in the
+// extended syntax, see DefaultParser.ParseFileContent.
+const ExtendedSyntaxHeader = "# t18n: extended"
+
+// DefaultParser parses .t18n files.
+type DefaultParser struct {
+	// Extended enables the extended syntax in all files, not only in
+	// files starting with ExtendedSyntaxHeader.
+	Extended bool
+}
+
+var _ DiagnosticParser = (*DefaultParser)(nil)
 
+// ParseFileContent parses lines like "Key=Value // Hint".
+//
+// A variant of the key is declared by the variant name in square brackets,
+// variants of the same key are merged into one Item:
+//
+//	Files[one]=# file
+//	Files[other]=# files
+//	Welcome[female]=Welcome, madam
+//
+// Value of the item having variants only is the variant "other".
+//
+// A context of the key is declared after ContextSeparator, items with the
+// same key and different contexts are distinct:
+//
+//	Open@button=Open
+//	Open@status=Opened
+//	Files@toolbar[one]=# file
+//
+// Keys, values and hints are trimmed, the value ends at the first
+// HintSeparator.
+//
+// Files starting with the line ExtendedSyntaxHeader use the extended
+// syntax described below. Other files are read as they always were,
+// unless the parser is Extended.
+//
+// Values and hints may contain escape sequences \n, \t, \r, \\, \/, \",
+// \= and \uXXXX, other backslashes are kept as is. "\/\/" is not
+// a HintSeparator:
+//
+//	Docs=See https:\/\/example.com // link to docs
+//
+// A line ending with a backslash continues on the next line, leading
+// spaces of the next line are removed:
+//
+//	Help=The first part, \
+//	     the second part.
+//
+// Quoted keys and values keep leading and trailing spaces, "//" and "="
+// in them:
+//
+//	"Key = 1"="  padded value  " // hint
+//
+// A value is quoted only if the closing quote ends it or precedes the
+// hint, otherwise quotes are a part of the value, like in
+// Title="Hamlet" by Shakespeare.
+//
+// A heredoc value spans lines up to the delimiter line, the indentation of
+// the delimiter line is removed from all lines, escapes are not replaced:
+//
+//	Terms=<<EOT // terms of use
+//	    First paragraph.
+//
+//	    Second paragraph.
+//	    EOT
+//
+// Problems of the content are ignored, see ParseFileDiagnostics.
 func (p *DefaultParser) ParseFileContent(data []byte) ([]Item, error) {
+	items, _, err := p.ParseFileDiagnostics(data)
+	return items, err
+}
+
+// ParseFileDiagnostics is like ParseFileContent but returns problems of
+// the content as well: malformed lines, empty keys, keys declared twice
+// and invalid UTF-8. Malformed lines and empty keys are skipped, the last
+// declaration of a key wins.
+func (p *DefaultParser) ParseFileDiagnostics(data []byte) ([]Item, Diagnostics, error) {
+
+	lines, err := readLines(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		res   []Item
+		diags Diagnostics
+	)
+	index := make(map[string]int)    // item id -> index in res
+	declared := make(map[string]int) // item id and variant -> line
+
+	for _, l := range scanLines(lines, p.Extended || isExtended(lines)) {
+		if !l.isEntry() {
+			continue
+		}
+		ln, indent, e := l.first+1, l.indent, l.entry
+
+		if !utf8.ValidString(l.text) {
+			diags = append(diags, Diagnostic{
+				Line:   ln,
+				Column: indent + invalidUTF8Column(l.text),
+				Err:    ErrInvalidUTF8,
+			})
+		}
+
+		if l.err != nil {
+			diags = append(diags, Diagnostic{
+				Line:   ln,
+				Column: indent + utf8.RuneCountInString(l.text[:l.pos]) + 1,
+				Err:    l.err,
+			})
+			continue
+		}
+
+		if e.key == "" {
+			diags = append(diags, Diagnostic{
+				Line:   ln,
+				Column: indent + 1,
+				Err:    ErrEmptyKey,
+			})
+			continue
+		}
+
+		id := itemID(e.context, e.key)
+		if prev, ok := declared[id+"["+e.variant+"]"]; ok {
+			diags = append(diags, Diagnostic{
+				Line:   ln,
+				Column: indent + 1,
+				Err:    fmt.Errorf("%w: %q, first declared at line %d", ErrDuplicateKey, e.rawKey, prev),
+			})
+		} else {
+			declared[id+"["+e.variant+"]"] = ln
+		}
+
+		if e.variant == "" {
+			if idx, ok := index[id]; ok {
+				// variants are declared before the plain value,
+				// or the key is declared twice.
+				res[idx].Value = e.value
+				if e.hint != "" || res[idx].Variants == nil {
+					res[idx].Hint = e.hint
+				}
+				continue
+			}
+			index[id] = len(res)
+			res = append(res, Item{Key: e.key, Context: e.context, Value: e.value, Hint: e.hint})
+			continue
+		}
+
+		idx, ok := index[id]
+		if !ok {
+			idx = len(res)
+			index[id] = idx
+			res = append(res, Item{Key: e.key, Context: e.context})
+		}
+
+		x := &res[idx]
+		x.SetVariant(e.variant, e.value)
+		if x.Hint == "" {
+			x.Hint = e.hint
+		}
+	}
+
+	for i := range res {
+		if res[i].Value == "" {
+			res[i].Value, _ = res[i].Variant(PluralOther.String())
+		}
+	}
 
-	var res []Item
+	return res, diags, nil
+}
+
+// readLines splits data into lines without line endings.
+func readLines(data []byte) ([]string, error) {
+	var lines []string
 	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+// isExtended reports if the lines start with ExtendedSyntaxHeader.
+func isExtended(lines []string) bool {
+	return len(lines) > 0 && strings.TrimSpace(lines[0]) == ExtendedSyntaxHeader
+}
+
+// logicalLine is a line of a .t18n file joined with its continuation
+// lines and heredoc body.
+type logicalLine struct {
+	// first is the index of the first physical line, n is the number
+	// of physical lines.
+	first, n int
+
+	// indent is the length of the indentation, text is the rest of
+	// the line with continuation lines joined.
+	indent int
+	text   string
+
+	entry entry
+
+	// err is a problem of the entry at the byte position pos of text.
+	err error
+	pos int
+}
+
+// isEntry reports if the line is neither blank nor a comment.
+func (l *logicalLine) isEntry() bool {
+	return l.text != "" && l.text[0] != '#'
+}
+
+// scanLines groups physical lines into logical lines and parses entries.
+// Continuation lines and heredocs are a part of the extended syntax.
+func scanLines(lines []string, extended bool) []logicalLine {
+	var res []logicalLine
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		l := logicalLine{first: i, indent: len(line) - len(strings.TrimLeft(line, " \t"))}
+		l.text = line[l.indent:]
+		if !l.isEntry() {
+			l.n = 1
+			res = append(res, l)
+			continue
+		}
+
+		// line continuation
+		for extended && hasContinuation(l.text) && i+1 < len(lines) {
+			i++
+			l.text = l.text[:len(l.text)-1] + strings.TrimLeft(lines[i], " \t")
+		}
+
+		l.entry, l.pos, l.err = parseEntry(l.text, extended)
+		if l.err == nil && l.entry.heredoc != "" {
+			n := 0
+			if l.entry.value, n = heredoc(lines[i+1:], l.entry.heredoc); n == 0 {
+				l.err = fmt.Errorf("%w: heredoc %q is not terminated", ErrMalformedLine, l.entry.heredoc)
+			}
+			i += n
+		}
+		l.n = i - l.first + 1
+		res = append(res, l)
+	}
+	return res
+}
+
+// invalidUTF8Column returns the column of the first invalid UTF-8
+// sequence of the line.
+func invalidUTF8Column(line string) int {
+	col := 1
+	for i, r := range line {
+		if r == utf8.RuneError {
+			if _, size := utf8.DecodeRuneInString(line[i:]); size == 1 {
+				return col
+			}
+		}
+		col++
+	}
+	return col
+}
+
+// hasContinuation reports if the line ends with an unescaped backslash.
+func hasContinuation(line string) bool {
+	n := len(line) - len(strings.TrimRight(line, `\`))
+	return n%2 == 1
+}
 
-	for {
-		if !scanner.Scan() {
+// heredoc returns lines up to the delimiter line joined by "\n" and
+// the number of lines including the delimiter, 0 if there is no delimiter.
+// Indentation of the delimiter line is removed from all lines.
+func heredoc(lines []string, delim string) (string, int) {
+	for i, line := range lines {
+		if strings.TrimSpace(line) != delim {
+			continue
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		body := make([]string, i)
+		for j := range body {
+			body[j] = strings.TrimPrefix(lines[j], indent)
+		}
+		return strings.Join(body, "\n"), i + 1
+	}
+	return "", 0
+}
+
+// splitVariant splits "Files[one]" into "Files" and "one".
+func splitVariant(key string) (string, string) {
+	if !strings.HasSuffix(key, "]") {
+		return key, ""
+	}
+	pos := strings.LastIndexByte(key, '[')
+	if pos <= 0 || pos == len(key)-2 {
+		return key, ""
+	}
+	return strings.TrimSpace(key[:pos]), strings.TrimSpace(key[pos+1 : len(key)-1])
+}
+
+// splitContext splits "Open@button" into "Open" and "button".
+func splitContext(key string) (string, string) {
+	pos := strings.LastIndex(key, ContextSeparator)
+	if ContextSeparator == "" || pos <= 0 || pos == len(key)-len(ContextSeparator) {
+		return key, ""
+	}
+	return strings.TrimSpace(key[:pos]), strings.TrimSpace(key[pos+len(ContextSeparator):])
+}
+
+// entry is a parsed logical line of a .t18n file.
+type entry struct {
+	rawKey                string
+	key, context, variant string
+	value, hint           string
+
+	// heredoc holds the delimiter of the value given by following lines.
+	heredoc string
+}
+
+// parseEntry parses the logical line "Key=Value // Hint". On error
+// it returns the byte position of the problem in the line.
+func parseEntry(line string, extended bool) (entry, int, error) {
+	var (
+		e    entry
+		rest string
+	)
+
+	if extended && line[0] == '"' {
+		// "quoted key"@context[variant]=Value
+		key, n, err := unquote(line)
+		if err != nil {
+			return e, n, err
+		}
+		vx := strings.IndexByte(line[n:], '=')
+		if vx == -1 {
+			return e, len(line), fmt.Errorf("%w: missing %q", ErrMalformedLine, "=")
+		}
+		suffix := strings.TrimSpace(line[n : n+vx])
+		e.rawKey = strings.TrimSpace(line[:n+vx])
+		if pos := strings.LastIndexByte(suffix, '['); pos != -1 && strings.HasSuffix(suffix, "]") {
+			e.variant = strings.TrimSpace(suffix[pos+1 : len(suffix)-1])
+			suffix = strings.TrimSpace(suffix[:pos])
+		}
+		if suffix != "" {
+			if !strings.HasPrefix(suffix, ContextSeparator) || ContextSeparator == "" {
+				return e, n, fmt.Errorf("%w: unexpected %q after quoted key", ErrMalformedLine, suffix)
+			}
+			e.context = strings.TrimSpace(suffix[len(ContextSeparator):])
+		}
+		e.key = key
+		rest = line[n+vx+1:]
+	} else {
+		vx := strings.IndexByte(line, '=')
+		if vx == -1 {
+			return e, 0, fmt.Errorf("%w: missing %q", ErrMalformedLine, "=")
+		}
+		e.rawKey = strings.TrimSpace(line[:vx])
+		key, variant := splitVariant(e.rawKey)
+		key, context := splitContext(key)
+		e.key, e.context, e.variant = key, context, variant
+		rest = line[vx+1:]
+		if !extended {
+			value, hint := rest, ""
+			if hx := strings.Index(rest, HintSeparator); hx != -1 {
+				value, hint = rest[:hx], rest[hx+len(HintSeparator):]
+			}
+			e.value, e.hint = strings.TrimSpace(value), strings.TrimSpace(hint)
+			return e, 0, nil
+		}
+		var err error
+		if e.key, err = unescape(key); err != nil {
+			return e, 0, err
+		}
+	}
+
+	rest = strings.TrimLeft(rest, " \t")
+	start := len(line) - len(rest)
+
+	switch {
+	case strings.HasPrefix(rest, `"`):
+		// the value is quoted only if the closing quote ends it or
+		// precedes the hint, values like "Hamlet" by Shakespeare are
+		// kept as is.
+		value, n, err := unquote(rest)
+		if err != nil {
 			break
 		}
+		tail := strings.TrimSpace(rest[n:])
+		if tail == "" {
+			e.value = value
+			return e, 0, nil
+		}
+		if HintSeparator != "" && strings.HasPrefix(tail, HintSeparator) {
+			e.value = value
+			if e.hint, err = unescape(strings.TrimSpace(tail[len(HintSeparator):])); err != nil {
+				return e, start + n, err
+			}
+			return e, 0, nil
+		}
 
-		if err := scanner.Err(); err != nil {
-			return nil, err
+	case strings.HasPrefix(rest, "<<"):
+		delim, tail := rest[2:], ""
+		if hx := indexUnescaped(delim, HintSeparator); hx != -1 {
+			delim, tail = delim[:hx], delim[hx+len(HintSeparator):]
+		}
+		if delim = strings.TrimSpace(delim); isIdentifier(delim) {
+			e.heredoc = delim
+			var err error
+			e.hint, err = unescape(strings.TrimSpace(tail))
+			return e, start, err
 		}
+	}
 
-		line := scanner.Text()
-		line = strings.TrimLeft(line, " ")
-		if len(line) == 0 || line[0] == '#' {
+	value, hint := rest, ""
+	if hx := indexUnescaped(rest, HintSeparator); hx != -1 {
+		value, hint = rest[:hx], rest[hx+len(HintSeparator):]
+	}
+
+	var err error
+	if e.value, err = unescape(strings.TrimSpace(value)); err != nil {
+		return e, start, err
+	}
+	if e.hint, err = unescape(strings.TrimSpace(hint)); err != nil {
+		return e, start + len(value), err
+	}
+	return e, 0, nil
+}
+
+// indexUnescaped returns the index of the first sep in s not preceded
+// by a backslash, or -1.
+func indexUnescaped(s, sep string) int {
+	if sep == "" {
+		return -1
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(s[i:], sep) {
+			return i
+		}
+	}
+	return -1
+}
+
+// unquote unescapes the quoted string at the start of s. It returns the
+// string and the number of consumed bytes including quotes.
+func unquote(s string) (string, int, error) {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			res, err := unescape(s[1:i])
+			return res, i + 1, err
+		}
+	}
+	return "", 0, fmt.Errorf("%w: unterminated quoted string", ErrMalformedLine)
+}
+
+// unescape replaces escape sequences \n, \t, \r, \\, \/, \", \= and
+// \uXXXX. Other backslashes are kept as is.
+func unescape(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' || i+1 == len(s) {
+			sb.WriteByte(c)
 			continue
 		}
 
-		item := p.parseLine(line)
-		if item != nil {
-			res = append(res, *item)
+		i++
+		switch s[i] {
+		case 'n':
+			sb.WriteByte('\n')
+		case 't':
+			sb.WriteByte('\t')
+		case 'r':
+			sb.WriteByte('\r')
+		case '\\', '/', '"', '=':
+			sb.WriteByte(s[i])
+		case 'u':
+			if i+5 > len(s) {
+				return "", fmt.Errorf("%w: invalid escape %q", ErrMalformedLine, s[i-1:])
+			}
+			r, err := strconv.ParseUint(s[i+1:i+5], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("%w: invalid escape %q", ErrMalformedLine, s[i-1:i+5])
+			}
+			sb.WriteRune(rune(r))
+			i += 4
+		default:
+			sb.WriteByte('\\')
+			sb.WriteByte(s[i])
 		}
 	}
+	return sb.String(), nil
+}
 
-	return res, nil
+// isIdentifier reports if s is a heredoc delimiter like "EOT".
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c == '_' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || i > 0 && c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
 }
 
-func (p *DefaultParser) parseLine(line string) *Item {
-	var res Item
+// Diagnostic is a problem found in a translation file.
+type Diagnostic struct {
+	// File is the file name, it's empty if the content is parsed
+	// without a file.
+	File string
+
+	// Line and Column are 1-based, the column counts runes.
+	Line   int
+	Column int
+
+	Err error
+}
 
-	vx := strings.Index(line, "=")
-	if vx == -1 {
-		return nil
+// Error returns the diagnostic like "en.t18n:3:1: empty key".
+func (d Diagnostic) Error() string {
+	pos := strconv.Itoa(d.Line) + ":" + strconv.Itoa(d.Column)
+	if d.File != "" {
+		pos = d.File + ":" + pos
 	}
+	return pos + ": " + d.Err.Error()
+}
 
-	res.Key = strings.TrimSpace(line[0:vx])
-	val := strings.TrimSpace(line[vx+1:])
-	hx := strings.Index(val, HintSeparator)
-	if hx != -1 {
-		res.Hint = strings.TrimSpace(val[hx+len(HintSeparator):])
-		res.Value = strings.TrimSpace(val[0:hx])
-	} else {
-		res.Value = val
+func (d Diagnostic) Unwrap() error {
+	return d.Err
+}
+
+// Diagnostics is a list of problems found in translation files.
+// It's returned as an error in the strict mode, see WithStrictParsing.
+type Diagnostics []Diagnostic
+
+// Error returns diagnostics, one per line.
+func (ds Diagnostics) Error() string {
+	lines := make([]string, len(ds))
+	for i := range ds {
+		lines[i] = ds[i].Error()
 	}
+	return strings.Join(lines, "\n")
+}
 
-	return &res
+// Unwrap returns diagnostics as errors, so errors.Is(err, ErrEmptyKey)
+// reports if any of them is an empty key.
+func (ds Diagnostics) Unwrap() []error {
+	res := make([]error, len(ds))
+	for i := range ds {
+		res[i] = ds[i]
+	}
+	return res
 }
@@ -0,0 +1,265 @@
+package i18n
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDefaultParser_ParseFileDiagnostics(t *testing.T) {
+	data := "# comment\n" +
+		"Save=Save\n" +
+		"  no separator\n" +
+		"=Empty\n" +
+		"Save=Store // later\n" +
+		"Files[one]=# file\n" +
+		"Files[one]=# files\n" +
+		"Files=# files\n" +
+		"Bad=\xffvalue\n"
+
+	var p DefaultParser
+	items, diags, err := p.ParseFileDiagnostics([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		line, column int
+		err          error
+	}{
+		{3, 3, ErrMalformedLine},
+		{4, 1, ErrEmptyKey},
+		{5, 1, ErrDuplicateKey},
+		{7, 1, ErrDuplicateKey},
+		{9, 5, ErrInvalidUTF8},
+	}
+	if len(diags) != len(expected) {
+		t.Fatalf("expected %d diagnostics, got %d: %v", len(expected), len(diags), diags)
+	}
+	for i, e := range expected {
+		d := diags[i]
+		if d.Line != e.line || d.Column != e.column || !errors.Is(d, e.err) {
+			t.Errorf("expected %d:%d: %v, got %v", e.line, e.column, e.err, d)
+		}
+	}
+	if got := diags[2].Error(); got != `5:1: duplicate key: "Save", first declared at line 2` {
+		t.Errorf("unexpected message: %s", got)
+	}
+
+	if len(items) != 3 {
+		t.Fatalf("expected 3 items, got %d", len(items))
+	}
+	if items[0].Value != "Store" || items[0].Hint != "later" {
+		t.Errorf("expected the last declaration of Save, got %+v", items[0])
+	}
+	if items[1].variants()["one"] != "# files" || items[1].Value != "# files" {
+		t.Errorf("unexpected Files item: %+v", items[1])
+	}
+}
+
+func TestContainer_StrictParsing(t *testing.T) {
+	storage := mapStorage{
+		"en.t18n": "Save=Save\nCancel\n",
+		"de.t18n": "Save=Speichern\nSave=Sichern\n",
+	}
+
+	t.Run("Strict", func(t *testing.T) {
+		r := NewLanguageRegistry()
+		tc := NewContainer(WithLanguageRegistry(r), WithStorage(storage), WithStrictParsing())
+		err := tc.ReadRegisteredFiles()
+
+		var diags Diagnostics
+		if !errors.As(err, &diags) {
+			t.Fatalf("expected Diagnostics, got %v", err)
+		}
+		if !errors.Is(err, ErrDuplicateKey) {
+			t.Errorf("expected ErrDuplicateKey, got %v", err)
+		}
+		if !errors.Is(err, ErrMalformedLine) {
+			t.Errorf("expected ErrMalformedLine of the second file, got %v", err)
+		}
+		if len(diags) != 2 {
+			t.Fatalf("expected diagnostics of both files, got %v", diags)
+		}
+		if got := err.Error(); got != "de.t18n:2:1: duplicate key: \"Save\", first declared at line 1\n"+
+			"en.t18n:2:1: malformed line: missing \"=\"" {
+			t.Errorf("unexpected error: %s", got)
+		}
+	})
+
+	t.Run("Lenient", func(t *testing.T) {
+		r := NewLanguageRegistry()
+		tc := NewContainer(WithLanguageRegistry(r), WithStorage(storage))
+		if err := tc.ReadRegisteredFiles(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		warnings := tc.Warnings()
+		if len(warnings) != 2 {
+			t.Fatalf("expected 2 warnings, got %v", warnings)
+		}
+		if got := warnings.Error(); got != "de.t18n:2:1: duplicate key: \"Save\", first declared at line 1\n"+
+			"en.t18n:2:1: malformed line: missing \"=\"" {
+			t.Errorf("unexpected warnings: %s", got)
+		}
+		if got := tc.Lang(r.Parse("de")).Value("Save"); got != "Sichern" {
+			t.Errorf("expected 'Sichern', got %q", got)
+		}
+	})
+}
+
+func TestDefaultParser_Grammar(t *testing.T) {
+	data := ExtendedSyntaxHeader + "\n" +
+		"Plain = Value // hint\n" +
+		`Docs=See https:\/\/example.com // link` + "\n" +
+		`Escapes=a\tb\nc \\ \u00e9 \d` + "\n" +
+		"Help=The first part, \\\n" +
+		"     the second part. // help\n" +
+		`Path=C:\\` + "\n" +
+		`"Key = 1"="  padded // value  " // quoted` + "\n" +
+		`"Open"@button[one]="Open \"it\""` + "\n" +
+		"Terms=<<EOT // terms\n" +
+		"    First paragraph.\n" +
+		"\n" +
+		"      # not a comment\n" +
+		"    EOT\n" +
+		"Empty=<<END\n" +
+		"END\n" +
+		"Next=Next\n" +
+		`Title="Hamlet" by Shakespeare // book` + "\n" +
+		`Quote="To be or not` + "\n"
+
+	var p DefaultParser
+	items, diags, err := p.ParseFileDiagnostics([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(diags) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	expected := []Item{
+		{Key: "Plain", Value: "Value", Hint: "hint"},
+		{Key: "Docs", Value: "See https://example.com", Hint: "link"},
+		{Key: "Escapes", Value: "a\tb\nc \\ é \\d"},
+		{Key: "Help", Value: "The first part, the second part.", Hint: "help"},
+		{Key: "Path", Value: `C:\`},
+		{Key: "Key = 1", Value: "  padded // value  ", Hint: "quoted"},
+		{Key: "Open", Context: "button", Variants: &Variants{"one": `Open "it"`}},
+		{Key: "Terms", Value: "First paragraph.\n\n  # not a comment", Hint: "terms"},
+		{Key: "Empty"},
+		{Key: "Next", Value: "Next"},
+		{Key: "Title", Value: `"Hamlet" by Shakespeare`, Hint: "book"},
+		{Key: "Quote", Value: `"To be or not`},
+	}
+	if len(items) != len(expected) {
+		t.Fatalf("expected %d items, got %d: %+v", len(expected), len(items), items)
+	}
+	for i, e := range expected {
+		got := items[i]
+		if got.Key != e.Key || got.Context != e.Context || got.Value != e.Value || got.Hint != e.Hint ||
+			len(got.variants()) != len(e.variants()) || got.variants()["one"] != e.variants()["one"] {
+			t.Errorf("expected %+v, got %+v", e, got)
+		}
+	}
+}
+
+func TestDefaultParser_GrammarErrors(t *testing.T) {
+	tests := []struct {
+		line   string
+		column int
+	}{
+		{`"Key"x=value`, 6},
+		{`"Key=value`, 1},
+		{`Key=\u12`, 5},
+		{"Key=<<EOT", 5},
+	}
+
+	p := DefaultParser{Extended: true}
+	for _, tt := range tests {
+		_, diags, err := p.ParseFileDiagnostics([]byte(tt.line))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(diags) != 1 || !errors.Is(diags[0], ErrMalformedLine) || diags[0].Column != tt.column {
+			t.Errorf("%s: expected malformed line at column %d, got %v", tt.line, tt.column, diags)
+		}
+	}
+}
+
+// baselineItems parses lines "Key=Value // Hint" like the parser did
+// before the extended syntax was added.
+func baselineItems(data string) []Item {
+	var res []Item
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimLeft(line, " ")
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		vx := strings.Index(line, "=")
+		if vx == -1 {
+			continue
+		}
+		item := Item{Key: strings.TrimSpace(line[:vx])}
+		val := strings.TrimSpace(line[vx+1:])
+		if hx := strings.Index(val, HintSeparator); hx != -1 {
+			item.Hint = strings.TrimSpace(val[hx+len(HintSeparator):])
+			val = strings.TrimSpace(val[:hx])
+		}
+		item.Value = val
+		res = append(res, item)
+	}
+	return res
+}
+
+func TestDefaultParser_Baseline(t *testing.T) {
+	fixtures := map[string]string{
+		"syntax": "# comment\n" +
+			"  Indented = value // hint // more\n" +
+			`Path=C:\new\` + "\n" +
+			"Next=not continued\n" +
+			`Quoted="quoted" // hint` + "\n" +
+			"Heredoc=<<EOT\n" +
+			"EOT=end\n" +
+			`Escapes=a\tb\nc \\ \u00e9` + "\n" +
+			"Hash=# files\n" +
+			"Empty=\n" +
+			"no separator\n",
+	}
+	names, err := filepath.Glob("testdata/*.t18n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		fixtures[name] = string(data)
+	}
+
+	var p DefaultParser
+	for name, data := range fixtures {
+		items, err := p.ParseFileContent([]byte(data))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		expected := baselineItems(data)
+		if len(items) != len(expected) {
+			t.Fatalf("%s: expected %d items, got %d: %+v", name, len(expected), len(items), items)
+		}
+		for i := range expected {
+			if !equalItems(items[i], expected[i]) {
+				t.Errorf("%s: expected %+v, got %+v", name, expected[i], items[i])
+			}
+		}
+	}
+
+	// the header enables the extended syntax.
+	items, _ := p.ParseFileContent([]byte(ExtendedSyntaxHeader + "\n" + `Path=C:\\new` + "\n"))
+	if len(items) != 1 || items[0].Value != `C:\new` {
+		t.Errorf(`expected 'C:\new', got %+v`, items)
+	}
+}
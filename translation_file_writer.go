@@ -0,0 +1,277 @@
+package i18n
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidName is returned for a context or a variant name which can't
+// be written to a .t18n file.
+var ErrInvalidName = errors.New("invalid context or variant name")
+
+// EncodeItems writes items in the .t18n format read by DefaultParser.
+//
+// Variants are written before the plain value, plural categories in the
+// CLDR order, the variant "other" is the last one. The plain value is
+// omitted if it equals the variant "other".
+//
+// Items are written in the extended syntax, starting with
+// ExtendedSyntaxHeader, only if some of them can't be written otherwise.
+// Then values are quoted or escaped if needed, multi-line values are
+// written as heredocs.
+//
+// Contexts and variant names are written as is, thus they can't contain
+// "=", "[", "]", quotes, HintSeparator, control characters or leading and
+// trailing spaces, contexts can't contain ContextSeparator. ErrInvalidName
+// is returned for them.
+func EncodeItems(w io.Writer, items []Item) error {
+	return encodeItems(w, items, nil)
+}
+
+// encodeItems is EncodeItems writing the comment lines of the item, if
+// comments is not nil, before its entries.
+func encodeItems(w io.Writer, items []Item, comments func(i int) []string) error {
+	var (
+		entries  [][]entry
+		extended bool
+	)
+	for i := range items {
+		if items[i].Key == "" {
+			return fmt.Errorf("item %d: %w", i, ErrEmptyKey)
+		}
+		if !isValidName(items[i].Context) || ContextSeparator != "" && strings.Contains(items[i].Context, ContextSeparator) {
+			return fmt.Errorf("item %d: %w: context %q", i, ErrInvalidName, items[i].Context)
+		}
+		for v := range items[i].variants() {
+			if v == "" || !isValidName(v) {
+				return fmt.Errorf("item %d: %w: variant %q", i, ErrInvalidName, v)
+			}
+		}
+		ies := itemEntries(&items[i])
+		for _, e := range ies {
+			extended = extended || !isPlainEntry(e)
+		}
+		entries = append(entries, ies)
+	}
+
+	bw := bufio.NewWriter(w)
+	if extended {
+		bw.WriteString(ExtendedSyntaxHeader + "\n")
+	}
+	for i := range entries {
+		if comments != nil {
+			for _, c := range comments(i) {
+				bw.WriteString("# " + c + "\n")
+			}
+		}
+		for _, e := range entries[i] {
+			for _, line := range encodeEntry(e, extended) {
+				bw.WriteString(line)
+				bw.WriteByte('\n')
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// itemEntries returns entries of the item in the written order.
+// The hint is assigned to the first entry.
+func itemEntries(item *Item) []entry {
+	variants := make([]string, 0, len(item.variants()))
+	for v := range item.variants() {
+		variants = append(variants, v)
+	}
+	sort.Slice(variants, func(i, j int) bool {
+		ri, rj := variantRank(variants[i]), variantRank(variants[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return variants[i] < variants[j]
+	})
+
+	res := make([]entry, 0, len(variants)+1)
+	for _, v := range variants {
+		res = append(res, entry{key: item.Key, context: item.Context, variant: v, value: item.variants()[v]})
+	}
+	if other, ok := item.Variant(PluralOther.String()); !ok || item.Value != other {
+		res = append(res, entry{key: item.Key, context: item.Context, value: item.Value})
+	}
+	res[0].hint = item.Hint
+	return res
+}
+
+// variantRank orders plural categories as CLDR does: zero, one, two,
+// few, many. Other variants, like genders, follow them, "other" is the
+// last one.
+func variantRank(variant string) int {
+	cat, ok := ParsePluralCategory(variant)
+	switch {
+	case !ok:
+		return int(PluralMany)
+	case cat == PluralOther:
+		return int(PluralMany) + 1
+	}
+	return int(cat) - 1
+}
+
+// isValidName reports if the context or the variant name can be written.
+func isValidName(name string) bool {
+	return name == strings.TrimSpace(name) &&
+		!strings.ContainsAny(name, "=[]\"") &&
+		!(HintSeparator != "" && strings.Contains(name, HintSeparator)) &&
+		!hasControl(name)
+}
+
+// isPlainEntry reports if the entry can be written without the extended
+// syntax.
+func isPlainEntry(e entry) bool {
+	return e.key == strings.TrimSpace(e.key) &&
+		!strings.ContainsAny(e.key, "=[]") &&
+		!(ContextSeparator != "" && strings.Contains(e.key, ContextSeparator)) &&
+		!strings.HasPrefix(e.key, "#") &&
+		!hasControl(e.key) &&
+		e.value == strings.TrimSpace(e.value) &&
+		!(HintSeparator != "" && strings.Contains(e.value, HintSeparator)) &&
+		!hasControl(e.value) &&
+		e.hint == strings.TrimSpace(e.hint) &&
+		!hasControl(e.hint)
+}
+
+// encodeEntry returns physical lines of the entry. Entries are written
+// as is unless the syntax is extended, see isPlainEntry.
+func encodeEntry(e entry, extended bool) []string {
+	var sb strings.Builder
+
+	switch {
+	case !extended:
+		sb.WriteString(e.key)
+	case keyNeedsQuotes(e.key):
+		sb.WriteString(quote(e.key))
+	default:
+		sb.WriteString(strings.ReplaceAll(e.key, `\`, `\\`))
+	}
+	if e.context != "" {
+		sb.WriteString(ContextSeparator + e.context)
+	}
+	if e.variant != "" {
+		sb.WriteString("[" + e.variant + "]")
+	}
+	sb.WriteByte('=')
+
+	if !extended {
+		sb.WriteString(e.value)
+		if e.hint != "" {
+			sb.WriteString(" " + HintSeparator + " " + e.hint)
+		}
+		return []string{sb.String()}
+	}
+
+	hint := ""
+	if e.hint != "" {
+		hint = " " + HintSeparator + " " + escapeHint(e.hint)
+	}
+
+	if delim, ok := heredocDelimiter(e.value); ok {
+		sb.WriteString("<<" + delim + hint)
+		lines := []string{sb.String()}
+		lines = append(lines, strings.Split(e.value, "\n")...)
+		return append(lines, delim)
+	}
+
+	if valueNeedsQuotes(e.value) {
+		sb.WriteString(quote(e.value))
+	} else {
+		sb.WriteString(strings.ReplaceAll(e.value, `\`, `\\`))
+	}
+	sb.WriteString(hint)
+	return []string{sb.String()}
+}
+
+// keyNeedsQuotes reports if the key can't be written as is.
+func keyNeedsQuotes(key string) bool {
+	return key != strings.TrimSpace(key) ||
+		strings.ContainsAny(key, "=[]\"") ||
+		ContextSeparator != "" && strings.Contains(key, ContextSeparator) ||
+		strings.HasPrefix(key, "#") ||
+		hasControl(key)
+}
+
+// valueNeedsQuotes reports if the value can't be written as is.
+func valueNeedsQuotes(value string) bool {
+	return value != strings.TrimSpace(value) ||
+		strings.HasPrefix(value, `"`) ||
+		strings.HasPrefix(value, "<<") ||
+		HintSeparator != "" && strings.Contains(value, HintSeparator) ||
+		hasControl(value)
+}
+
+func hasControl(s string) bool {
+	return strings.IndexFunc(s, unicode.IsControl) != -1
+}
+
+// heredocDelimiter returns a delimiter for the multi-line value, which
+// does not occur in it. Values with control characters other than new
+// lines are quoted instead.
+func heredocDelimiter(value string) (string, bool) {
+	if !strings.Contains(value, "\n") ||
+		strings.IndexFunc(value, func(r rune) bool { return r != '\n' && unicode.IsControl(r) }) != -1 {
+		return "", false
+	}
+
+	lines := strings.Split(value, "\n")
+	for i := 0; ; i++ {
+		delim := "EOT"
+		if i > 0 {
+			delim = fmt.Sprintf("EOT%d", i)
+		}
+
+		found := false
+		for _, line := range lines {
+			if strings.TrimSpace(line) == delim {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return delim, true
+		}
+	}
+}
+
+// quote returns s in double quotes with escape sequences.
+func quote(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '\\', '"':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '\r':
+			sb.WriteString(`\r`)
+		default:
+			if unicode.IsControl(r) {
+				fmt.Fprintf(&sb, `\u%04x`, r)
+				continue
+			}
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// escapeHint escapes backslashes and control characters of the hint.
+func escapeHint(hint string) string {
+	q := quote(strings.TrimSpace(hint))
+	return strings.ReplaceAll(q[1:len(q)-1], `\"`, `"`)
+}
@@ -0,0 +1,253 @@
+package i18n
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestEncodeItems(t *testing.T) {
+	items := []Item{
+		{Key: "Save", Value: "Save", Hint: "button"},
+		{Key: "Docs", Value: "See https://example.com"},
+		{Key: "Padded", Value: "  padded  "},
+		{Key: "Path", Value: `C:\new\`},
+		{Key: "Key = 1", Value: "\"quoted\""},
+		{Key: "Open", Context: "status", Value: "Opened"},
+		{Key: "Files", Value: "# files", Hint: "count", Variants: &Variants{"other": "# files", "one": "# file", "few": "# files"}},
+		{Key: "Gender", Value: "Hi", Variants: &Variants{"female": "Hi, madam", "other": "Hello"}},
+		{Key: "Terms", Value: "First.\n\nEOT\nLast.", Hint: "terms"},
+		{Key: "Tab", Value: "a\tb"},
+		{Key: "Empty"},
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeItems(&buf, items); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := ExtendedSyntaxHeader + `
+Save=Save // button
+Docs="See https://example.com"
+Padded="  padded  "
+Path=C:\\new\\
+"Key = 1"="\"quoted\""
+Open@status=Opened
+Files[one]=# file // count
+Files[few]=# files
+Files[other]=# files
+Gender[female]=Hi, madam
+Gender[other]=Hello
+Gender=Hi
+Terms=<<EOT1 // terms
+First.
+
+EOT
+Last.
+EOT1
+Tab="a\tb"
+Empty=
+`
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+
+	var p DefaultParser
+	parsed, diags, err := p.ParseFileDiagnostics(buf.Bytes())
+	if err != nil || len(diags) != 0 {
+		t.Fatalf("unexpected error: %v %v", err, diags)
+	}
+	if len(parsed) != len(items) {
+		t.Fatalf("expected %d items, got %d", len(items), len(parsed))
+	}
+	for i := range items {
+		if !equalItems(items[i], parsed[i]) {
+			t.Errorf("expected %+v, got %+v", items[i], parsed[i])
+		}
+	}
+
+	if err := EncodeItems(&buf, []Item{{Value: "x"}}); !errors.Is(err, ErrEmptyKey) {
+		t.Errorf("expected ErrEmptyKey, got %v", err)
+	}
+
+	// items written without the extended syntax
+	buf.Reset()
+	if err := EncodeItems(&buf, []Item{{Key: "Path", Value: `C:\new\`, Hint: "dir"}, {Key: "Title", Value: `"Hamlet"`}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := buf.String(); got != "Path=C:\\new\\ // dir\nTitle=\"Hamlet\"\n" {
+		t.Errorf("unexpected output: %q", got)
+	}
+}
+
+func equalItems(a, b Item) bool {
+	if a.Key != b.Key || a.Context != b.Context || a.Value != b.Value || a.Hint != b.Hint || len(a.variants()) != len(b.variants()) {
+		return false
+	}
+	for k, v := range a.variants() {
+		if b.variants()[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func TestDocument(t *testing.T) {
+	data := ExtendedSyntaxHeader + "\n" +
+		"# Buttons\n" +
+		"Save = Save   // button\n" +
+		"Cancel=Cancel\n" +
+		"\n" +
+		"# Files\n" +
+		"Files[one]=# file\n" +
+		"Files[other]=# files // count\n" +
+		"Files=# files\n" +
+		"broken line\n" +
+		"Help=First, \\\n" +
+		"     second.\n"
+
+	doc, err := ParseDocument([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := string(doc.Bytes()); got != data {
+		t.Fatalf("expected lossless output, got:\n%s", got)
+	}
+
+	item, ok := doc.Item("Cancel", "")
+	if !ok {
+		t.Fatalf("expected Cancel")
+	}
+	item.Value = "Abort"
+	doc.Set(item)
+
+	files, _ := doc.Item("Files", "")
+	files.SetVariant("few", "# files!")
+	doc.Set(files)
+
+	doc.Set(Item{Key: "Open", Context: "status", Value: "Opened"})
+	if !doc.Delete("Help", "") {
+		t.Errorf("expected Help deleted")
+	}
+	if doc.Delete("Missing", "") {
+		t.Errorf("expected Missing not found")
+	}
+
+	expected := ExtendedSyntaxHeader + "\n" +
+		"# Buttons\n" +
+		"Save = Save   // button\n" +
+		"Cancel=Abort\n" +
+		"\n" +
+		"# Files\n" +
+		"Files[one]=# file\n" +
+		"Files[other]=# files // count\n" +
+		"Files=# files\n" +
+		"Files[few]=# files!\n" +
+		"broken line\n" +
+		"Open@status=Opened\n"
+	if got := string(doc.Bytes()); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+
+	files.Hint = "number of files"
+	delete(*files.Variants, "few")
+	doc.Set(files)
+	if got, _ := doc.Item("Files", ""); !equalItems(got, files) {
+		t.Errorf("expected %+v, got %+v", files, got)
+	}
+}
+
+func TestDocument_CRLF(t *testing.T) {
+	doc, err := ParseDocument([]byte("A=1\r\nB=2"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	doc.Set(Item{Key: "C", Value: "3"})
+	if got := string(doc.Bytes()); got != "A=1\r\nB=2\r\nC=3" {
+		t.Errorf("unexpected output: %q", got)
+	}
+}
+
+func TestDocument_Extend(t *testing.T) {
+	data := "# Paths\n" +
+		`Path=C:\new\` + "\n" +
+		`Title="Hamlet"` + "\n" +
+		"broken \\\n" +
+		"Save=Save // button\n"
+
+	doc, err := ParseDocument([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	before := doc.Items()
+
+	doc.Set(Item{Key: "Padded", Value: "  padded  "})
+
+	expected := ExtendedSyntaxHeader + "\n" +
+		"# Paths\n" +
+		`Path=C:\\new\\` + "\n" +
+		`Title="\"Hamlet\""` + "\n" +
+		"# broken \\\n" +
+		"Save=Save // button\n" +
+		`Padded="  padded  "` + "\n"
+	if got := string(doc.Bytes()); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+
+	after := doc.Items()
+	if len(after) != len(before)+1 {
+		t.Fatalf("expected %d items, got %+v", len(before)+1, after)
+	}
+	for i := range before {
+		if !equalItems(before[i], after[i]) {
+			t.Errorf("expected %+v, got %+v", before[i], after[i])
+		}
+	}
+}
+
+func TestEncodeItems_Roundtrip(t *testing.T) {
+	items := []Item{
+		{Key: "a=b", Context: "c d", Value: "v=w"},
+		{Key: "[x]", Context: "#1", Value: "[y]"},
+		{Key: `"q"`, Context: "a.b", Value: `"q"`, Hint: `"h" // h`},
+		{Key: "k // h", Value: "v // h", Hint: "h"},
+		{Key: " k ", Variants: &Variants{"one": " v ", "other": "=v="}, Value: "=v="},
+		{Key: "k@c", Context: "c", Value: "v"},
+		{Key: `k\`, Value: `v\`},
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeItems(&buf, items); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var p DefaultParser
+	parsed, diags, err := p.ParseFileDiagnostics(buf.Bytes())
+	if err != nil || len(diags) != 0 {
+		t.Fatalf("unexpected error: %v %v", err, diags)
+	}
+	if len(parsed) != len(items) {
+		t.Fatalf("expected %d items, got %+v", len(items), parsed)
+	}
+	for i := range items {
+		if !equalItems(items[i], parsed[i]) {
+			t.Errorf("expected %+v, got %+v", items[i], parsed[i])
+		}
+	}
+
+	invalid := []Item{
+		{Key: "ctx", Context: "a=b", Value: "v"},
+		{Key: "ctx", Context: "a[b]", Value: "v"},
+		{Key: "ctx", Context: `"a"`, Value: "v"},
+		{Key: "ctx", Context: "a // b", Value: "v"},
+		{Key: "ctx", Context: " a", Value: "v"},
+		{Key: "ctx", Context: "a@b", Value: "v"},
+		{Key: "ctx", Variants: &Variants{"a]": "v"}},
+		{Key: "ctx", Variants: &Variants{"a=b": "v"}},
+		{Key: "ctx", Variants: &Variants{"": "v"}},
+	}
+	for _, item := range invalid {
+		if err := EncodeItems(&buf, []Item{item}); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("%+v: expected ErrInvalidName, got %v", item, err)
+		}
+	}
+}
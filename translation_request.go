@@ -30,18 +30,34 @@ func (tc *TranslationContainer) Namespace(namespace string, li Language) Transla
 	}
 }
 
+// Language returns the requested language.
+func (tr TranslationRequest) Language() Language {
+	return tr.lang
+}
+
+// Namespace returns the requested namespace.
+func (tr TranslationRequest) Namespace() string {
+	return tr.namespace
+}
+
+// value returns the item of the key, or the item made by the strategy
+// if the key is not found, and reports the lookup.
 func (tr TranslationRequest) value(key string) Item {
 
-	for {
-		if res, ok := tr.item(key); ok {
-			return res
-		}
+	if tr.tc == nil {
+		return Item{Key: key, Value: key}
+	}
 
-		if tr.lang = NextLanguage(tr.lang); tr.lang == Unknown {
-			break
-		}
+	res, li, ok := tr.lookup(key)
+	tr.report(key, li, ok)
+	if ok {
+		return res
 	}
+	return tr.notFound(key)
+}
 
+// notFound returns the item of the key not found, see WithStrategy.
+func (tr TranslationRequest) notFound(key string) Item {
 	switch tr.tc.cfg.strategy {
 	case ReturnResourceCode:
 		return Item{Key: key, Value: key}
@@ -57,85 +73,237 @@ func (tr TranslationRequest) Value(key string) string {
 	return tr.value(key).Value
 }
 
+// ValueCtx returns a translation value for a specific key in the context,
+// like "Open" on a button or on a status badge. Every language of the
+// fallback chain is tried for the key in the context first, then for the
+// key without context, so a translation in the requested language wins
+// over a translation in the context in a fallback language.
+func (tr TranslationRequest) ValueCtx(context, key string) string {
+	if tr.tc == nil || context == "" {
+		return tr.value(key).Value
+	}
+
+	plain := tr.unbracket(key)
+	id := itemID(context, plain)
+	res, li, ok := tr.walk(tr.tc.snapshot(), nil, id, plain)
+	tr.report(id, li, ok)
+	if !ok {
+		return tr.notFound(key).Value
+	}
+	return res.Value
+}
+
+// Select returns the variant of the key chosen by the selector, like
+// a gender of the addressee. If the item has no such variant, the variant
+// "other" is used. If neither is found, the fallback chain of the language
+// is walked. The value of the key is returned if there are no variants.
+//
+// Variants are declared in .t18n files as:
+//
+//	Welcome[male]=Vítej, milý uživateli
+//	Welcome[female]=Vítej, milá uživatelko
+//	Welcome[other]=Vítejte
+func (tr TranslationRequest) Select(key, selector string) string {
+	if tr.tc == nil {
+		return key
+	}
+
+	var variant string
+	_, li, ok := tr.lookupMatch(key, func(item *Item, li Language) bool {
+		var ok bool
+		if variant, ok = item.Variant(selector); ok {
+			return true
+		}
+		variant, ok = item.Variant(PluralOther.String())
+		return ok
+	})
+	if !ok {
+		return tr.value(key).Value
+	}
+	tr.report(key, li, true)
+	return variant
+}
+
 // Hint returns a hint for a specific key.
 func (tr TranslationRequest) Hint(key string) string {
 	return tr.value(key).Hint
 }
 
-func (tr TranslationRequest) item(id string) (Item, bool) {
+// lookup walks the fallback chain of the requested language in every
+// namespace of the namespace chain, then the primary language.
+// Returns the found item and its language.
+func (tr TranslationRequest) lookup(id string) (Item, Language, bool) {
+	return tr.lookupMatch(id, nil)
+}
 
-	if len(id) > 2 &&
-		tr.tc.cfg.bracketSymbol != "" &&
-		strings.HasPrefix(id, tr.tc.cfg.bracketSymbol) &&
-		strings.HasSuffix(id, tr.tc.cfg.bracketSymbol) {
-		id = id[1 : len(id)-1]
+// lookupMatch is like lookup but skips items for which accept returns false.
+// The item passed to accept shall not be modified.
+func (tr TranslationRequest) lookupMatch(id string, accept func(item *Item, li Language) bool) (Item, Language, bool) {
+	if res, li, ok := tr.lookupIn(tr.tc.snapshot(), id, accept); ok {
+		return *res, li, true
 	}
+	return Item{}, Unknown, false
+}
 
-	rsi, ok := tr.tc.translations[key{lang: tr.lang, namespace: tr.namespace}]
-	if ok {
-		var idx int
-		if idx, ok = rsi.index[id]; ok {
-			return rsi.items[idx], true
+// lookupIn is like lookupMatch but walks the given snapshot and returns
+// the pointer to the item kept in the snapshot.
+func (tr TranslationRequest) lookupIn(snap *snapshot, id string, accept func(item *Item, li Language) bool) (*Item, Language, bool) {
+	id = tr.unbracket(id)
+	return tr.walk(snap, accept, id)
+}
+
+// report passes the result of a public lookup of the id to metrics and
+// the missing handler: the language the returned item is found in, or
+// Unknown if the item is made by the strategy. Every public call reports
+// once.
+func (tr TranslationRequest) report(id string, li Language, found bool) {
+	if m := tr.tc.metrics; m != nil {
+		m.record(tr.lang, tr.namespace, li, found)
+	}
+	if h := tr.tc.cfg.missingHandler; h != nil && li != tr.lang {
+		h(tr.lang, tr.namespace, displayID(tr.unbracket(id)), li)
+	}
+}
+
+// walk walks the namespace chain and the fallback chain for lookupIn.
+// Ids are tried in the given order in every namespace and language.
+func (tr TranslationRequest) walk(snap *snapshot, accept func(item *Item, li Language) bool, ids ...string) (*Item, Language, bool) {
+	namespaces := snap.namespaceChain(tr.namespace)
+	for _, langs := range tr.passes(snap) {
+		for _, ns := range namespaces {
+			for _, li := range langs {
+				for _, id := range ids {
+					if res, ok := tr.item(snap, li, ns, id); ok && (accept == nil || accept(res, li)) {
+						return res, li, true
+					}
+				}
+			}
 		}
 	}
+	return nil, Unknown, false
+}
 
-	if tr.tc.cfg.primaryLanguage == Unknown {
-		return Item{}, false
+// unbracket removes the bracket symbols around the id.
+func (tr TranslationRequest) unbracket(id string) string {
+	if len(id) > 2 &&
+		tr.tc.cfg.bracketSymbol != "" &&
+		strings.HasPrefix(id, tr.tc.cfg.bracketSymbol) &&
+		strings.HasSuffix(id, tr.tc.cfg.bracketSymbol) {
+		return id[1 : len(id)-1]
 	}
+	return id
+}
 
-	rsi, ok = tr.tc.translations[key{lang: tr.tc.cfg.primaryLanguage, namespace: tr.namespace}]
+// item returns item from the set of the language li in the namespace ns.
+func (tr TranslationRequest) item(snap *snapshot, li Language, ns string, id string) (*Item, bool) {
+	rsi, ok := snap.translations[key{lang: li, namespace: ns}]
 	if ok {
 		var idx int
 		if idx, ok = rsi.index[id]; ok {
-			return rsi.items[idx], true
+			return &rsi.items[idx], true
 		}
 	}
-	return Item{}, false
+	return nil, false
 }
 
+// ValueWithDefault returns a translation value for a specific key or
+// notFoundValue if the key is not found.
 func (tr TranslationRequest) ValueWithDefault(id string, notFoundValue string) string {
-	res, ok := tr.item(id)
+	if tr.tc == nil {
+		return notFoundValue
+	}
+	res, li, ok := tr.lookup(id)
+	tr.report(id, li, ok)
 	if !ok {
 		return notFoundValue
 	}
 	return res.Value
 }
 
-// JSON returns translation in JSON format.
-func (tr TranslationRequest) JSON() ([]byte, error) {
-	kv := make(map[string]ResponseItem)
-	set, ok := tr.tc.translations[key{lang: tr.lang}]
-	if !ok {
-		if tr.tc.cfg.primaryLanguage != Unknown {
-			set, ok = tr.tc.translations[key{lang: tr.tc.cfg.primaryLanguage}]
+// jsonConfig defines options for TranslationRequest.JSON.
+type jsonConfig struct {
+	// markInherited sets ResponseItem.Inherited.
+	markInherited bool
+}
+
+type JSONOption func(o *jsonConfig)
+
+// WithInheritedMark marks items taken from a language other than
+// the requested one, so the client can flag untranslated strings.
+func WithInheritedMark() JSONOption {
+	return func(o *jsonConfig) {
+		o.markInherited = true
+	}
+}
+
+// passes returns languages walked in every namespace of the namespace
+// chain: the fallback chain of the requested language first, then the
+// primary language if it's not in the chain. Chains are read from the
+// snapshot without locking the registry.
+func (tr TranslationRequest) passes(snap *snapshot) [][]Language {
+	chain := snap.chain(tr.tc.Registry(), tr.lang)
+	primary := tr.tc.cfg.primaryLanguage
+	if primary == Unknown {
+		return [][]Language{chain}
+	}
+	for _, li := range chain {
+		if li == primary {
+			return [][]Language{chain}
 		}
 	}
-	if !ok {
+	return [][]Language{chain, {primary}}
+}
+
+// JSON returns translation in JSON format.
+//
+// Items are merged from the requested namespace, its parents and the
+// default namespace (see WithNamespaceParent). The fallback chain of
+// the requested language is walked in all namespaces first, then the
+// primary language. The first found item of a key wins. Keys of items having a context are written like in
+// a .t18n file: "Open@button".
+func (tr TranslationRequest) JSON(opts ...JSONOption) ([]byte, error) {
+	if tr.tc == nil {
 		return nil, errors.New("no translation found")
 	}
 
-	for _, item := range set.items {
-		kv[tr.tc.genKey(item.Key)] = ResponseItem{
-			Value: item.Value,
-			Hint:  item.Hint,
-		}
+	var cfg jsonConfig
+	for _, opt := range opts {
+		opt(&cfg)
 	}
 
-	if tr.tc.cfg.primaryLanguage != Unknown && tr.tc.cfg.primaryLanguage != tr.lang {
-		if set, ok = tr.tc.translations[key{lang: tr.tc.cfg.primaryLanguage}]; ok {
-			for _, item := range set.items {
-				k := tr.tc.genKey(item.Key)
-				if _, ok := kv[k]; ok {
+	kv := make(map[string]ResponseItem)
+	snap := tr.tc.snapshot()
+	namespaces := snap.namespaceChain(tr.namespace)
+
+	found := false
+	for _, langs := range tr.passes(snap) {
+		for _, ns := range namespaces {
+			for _, li := range langs {
+				set, ok := snap.translations[key{lang: li, namespace: ns}]
+				if !ok {
 					continue
 				}
-				kv[k] = ResponseItem{
-					Value: item.Value,
-					Hint:  item.Hint,
+				found = true
+
+				for _, item := range set.items {
+					k := tr.tc.genKey(displayID(item.id()))
+					if _, ok := kv[k]; ok {
+						continue
+					}
+					kv[k] = ResponseItem{
+						Value:     item.Value,
+						Hint:      item.Hint,
+						Inherited: cfg.markInherited && li != tr.lang,
+					}
 				}
 			}
 		}
 	}
 
+	if !found {
+		return nil, errors.New("no translation found")
+	}
+
 	buf, err := json.Marshal(kv)
 	return buf, err
 }
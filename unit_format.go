@@ -0,0 +1,103 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownUnit is returned if there is no data for a measurement unit.
+var ErrUnknownUnit = errors.New("unknown unit")
+
+// Unit is a CLDR measurement unit identifier.
+type Unit string
+
+const (
+	UnitKilometer        Unit = "kilometer"
+	UnitMeter            Unit = "meter"
+	UnitCentimeter       Unit = "centimeter"
+	UnitMile             Unit = "mile"
+	UnitKilogram         Unit = "kilogram"
+	UnitGram             Unit = "gram"
+	UnitLiter            Unit = "liter"
+	UnitDay              Unit = "day"
+	UnitHour             Unit = "hour"
+	UnitMinute           Unit = "minute"
+	UnitSecond           Unit = "second"
+	UnitMegabyte         Unit = "megabyte"
+	UnitGigabyte         Unit = "gigabyte"
+	UnitCelsius          Unit = "celsius"
+	UnitKilometerPerHour Unit = "kilometer-per-hour"
+)
+
+// UnitWidth is a length of unit names.
+type UnitWidth int8
+
+const (
+	// UnitLong spells units out: "5 kilometers".
+	UnitLong UnitWidth = iota
+	// UnitShort abbreviates units: "5 km".
+	UnitShort
+	// UnitNarrow is the shortest form: "5km".
+	UnitNarrow
+)
+
+// UnitFormat formats measurements by CLDR data of a language.
+type UnitFormat struct {
+	code    string
+	units   resolvedUnits
+	numbers NumberFormat
+}
+
+// Units returns the unit format of the language li of the default registry.
+func Units(li Language) UnitFormat {
+	return defaultRegistry.Units(li)
+}
+
+// Units returns the unit format of the language li. CLDR data is taken
+// following the fallback chain of li, like dates.
+//
+// Built-in data covers cs, de, en, en-GB, es, fr, ja, pt and ru. Other
+// locales use the data of their parent and fallback languages, and
+// English if none of them is covered.
+func (r *LanguageRegistry) Units(li Language) UnitFormat {
+	code := r.Code(li)
+	return UnitFormat{
+		code:    code,
+		units:   resolveUnitData(r.chainCodes(li)),
+		numbers: newNumberFormat(code),
+	}
+}
+
+// Units returns the unit format of the requested language.
+func (tr TranslationRequest) Units() UnitFormat {
+	if tr.tc == nil {
+		return defaultRegistry.Units(tr.lang)
+	}
+	return tr.tc.Registry().Units(tr.lang)
+}
+
+// Format formats n of the unit: "5 kilometers" in English with UnitLong,
+// "5 kilometrů" in Czech, "5 km" with UnitShort. The unit name agrees
+// with n by plural rules of the language.
+//
+// If the unit is unknown, n and the unit identifier are returned with
+// ErrUnknownUnit.
+func (uf UnitFormat) Format(n any, unit Unit, width UnitWidth) (string, error) {
+	num := uf.numbers.Decimal(n)
+
+	patterns, ok := uf.units[unit]
+	if !ok {
+		return num + " " + string(unit), fmt.Errorf("%w: %q", ErrUnknownUnit, unit)
+	}
+	if width < UnitLong || width > UnitNarrow {
+		width = UnitShort
+	}
+
+	p := patterns[width]
+	pattern, ok := p[pluralCategory(cardinalRuleSet(), uf.code, n)]
+	if !ok {
+		pattern = p[PluralOther]
+	}
+	return strings.ReplaceAll(pattern, "{0}", num), nil
+}
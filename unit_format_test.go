@@ -0,0 +1,83 @@
+package i18n
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUnitFormat(t *testing.T) {
+	r := NewLanguageRegistry()
+
+	tests := []struct {
+		code     string
+		n        any
+		unit     Unit
+		width    UnitWidth
+		expected string
+	}{
+		{"en", 1, UnitKilometer, UnitLong, "1 kilometer"},
+		{"en", 5, UnitKilometer, UnitLong, "5 kilometers"},
+		{"en", 5, UnitKilometer, UnitShort, "5 km"},
+		{"en", 5, UnitKilometer, UnitNarrow, "5km"},
+		{"en", 1.5, UnitHour, UnitLong, "1.5 hours"},
+		{"en", 1234, UnitMeter, UnitLong, "1,234 meters"},
+		{"en-GB", 5, UnitKilometer, UnitLong, "5 kilometres"},
+		{"en-GB", 1, UnitHour, UnitLong, "1 hour"},
+		{"de", 2, UnitHour, UnitLong, "2 Stunden"},
+		{"de", 2, UnitHour, UnitNarrow, "2 Std."},
+		{"de", 1.5, UnitKilogram, UnitShort, "1,5 kg"},
+		{"fr", 2, UnitDay, UnitLong, "2\u00a0jours"},
+		{"cs", 1, UnitKilometer, UnitLong, "1 kilometr"},
+		{"cs", 3, UnitKilometer, UnitLong, "3 kilometry"},
+		{"cs", 5, UnitKilometer, UnitLong, "5 kilometrů"},
+		{"cs", 1.5, UnitKilometer, UnitLong, "1,5 kilometru"},
+		{"ru", 21, UnitMinute, UnitLong, "21 минута"},
+		{"ru", 5, UnitMinute, UnitLong, "5 минут"},
+		{"es", 2, UnitHour, UnitLong, "2 horas"},
+		{"es-MX", 1, UnitKilometer, UnitLong, "1 kilómetro"},
+		{"pt", 1, UnitDay, UnitLong, "1 dia"},
+		{"pt-BR", 3, UnitDay, UnitShort, "3 dias"},
+		{"de-CH", 2, UnitHour, UnitLong, "2 Stunden"},
+		{"ja", 5, UnitKilometer, UnitLong, "5 キロメートル"},
+		{"ja-JP", 3, UnitHour, UnitNarrow, "3時間"},
+	}
+
+	for _, tt := range tests {
+		got, err := r.Units(r.Parse(tt.code)).Format(tt.n, tt.unit, tt.width)
+		if err != nil {
+			t.Errorf("%s %v %s: unexpected error: %v", tt.code, tt.n, tt.unit, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("%s %v %s: expected %q, got %q", tt.code, tt.n, tt.unit, tt.expected, got)
+		}
+	}
+}
+
+func TestUnitFormat_UnknownUnit(t *testing.T) {
+	got, err := Units(Unknown).Format(3, "furlong", UnitLong)
+	if !errors.Is(err, ErrUnknownUnit) {
+		t.Errorf("expected ErrUnknownUnit, got %v", err)
+	}
+	if got != "3 furlong" {
+		t.Errorf("expected '3 furlong', got %q", got)
+	}
+}
+
+func TestTranslationRequest_Units(t *testing.T) {
+	r := NewLanguageRegistry()
+	tc := NewContainer(WithLanguageRegistry(r))
+	req := tc.Lang(r.Parse("de"))
+
+	km, err := req.Units().Format(5, UnitKilometer, UnitLong)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	h, err := req.Units().Format(2, UnitHour, UnitLong)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := req.Lists().Join([]string{km, h}, ListUnit); got != "5 Kilometer, 2 Stunden" {
+		t.Errorf("expected '5 Kilometer, 2 Stunden', got %q", got)
+	}
+}
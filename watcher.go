@@ -0,0 +1,251 @@
+package i18n
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// notifier signals changes in watched directories.
+type notifier interface {
+	// Changes returns a channel receiving a value after a change.
+	Changes() <-chan struct{}
+	Close() error
+}
+
+// watcherConfig defines options for Watcher.
+type watcherConfig struct {
+	// debounce is a quiet period after the last change before reload.
+	debounce time.Duration
+
+	// pollInterval is an interval of directory scanning if polling is
+	// used, or of waiting for a watched directory to reappear.
+	pollInterval time.Duration
+
+	// polling forces polling even if native notifications are available.
+	polling bool
+
+	onReload func(err error)
+}
+
+type WatcherOption func(o *watcherConfig)
+
+// WithDebounce assigns a quiet period after the last change before
+// the container is reloaded. The default is 300ms.
+func WithDebounce(d time.Duration) WatcherOption {
+	return func(o *watcherConfig) {
+		o.debounce = d
+	}
+}
+
+// WithPollInterval assigns an interval of directory scanning used if
+// native notifications are not available, or while a watched directory
+// is missing. The default is 2s.
+func WithPollInterval(d time.Duration) WatcherOption {
+	return func(o *watcherConfig) {
+		o.pollInterval = d
+	}
+}
+
+// WithPolling forces polling even if native notifications are available.
+// It's useful for network file systems not delivering inotify events.
+func WithPolling() WatcherOption {
+	return func(o *watcherConfig) {
+		o.polling = true
+	}
+}
+
+// WithReloadCallback assigns a function called after every reload
+// triggered by the watcher. err is nil if the reload succeeded.
+func WithReloadCallback(fn func(err error)) WatcherOption {
+	return func(o *watcherConfig) {
+		o.onReload = fn
+	}
+}
+
+// Watcher watches directories registered in LocalFileStorage and
+// reloads TranslationContainer on changes.
+//
+// Changes are detected by inotify on Linux, other systems fall back
+// to polling.
+type Watcher struct {
+	tc      *TranslationContainer
+	storage *LocalFileStorage
+	cfg     watcherConfig
+
+	// ready is closed once Run watches the directories.
+	ready     chan struct{}
+	readyOnce sync.Once
+}
+
+// NewWatcher creates a watcher of directories registered in the storage.
+// The storage is expected to be the storage of the container.
+func NewWatcher(tc *TranslationContainer, storage *LocalFileStorage, opts ...WatcherOption) *Watcher {
+	w := Watcher{
+		tc:      tc,
+		storage: storage,
+		ready:   make(chan struct{}),
+		cfg: watcherConfig{
+			debounce:     300 * time.Millisecond,
+			pollInterval: 2 * time.Second,
+		},
+	}
+
+	for _, opt := range opts {
+		opt(&w.cfg)
+	}
+	return &w
+}
+
+// Run watches directories until ctx is done. On every change, after the
+// debounce period, files are registered again (see LocalFileStorage.Refresh)
+// and the container is reloaded. If the reload fails, previously loaded
+// translations stay in use.
+//
+// Run returns nil when ctx is done, or an error if watching can not start.
+func (w *Watcher) Run(ctx context.Context) error {
+
+	regs := w.storage.fileRegistrations()
+
+	var (
+		n   notifier
+		err error
+	)
+	if !w.cfg.polling {
+		n, err = newNativeNotifier(regs, w.cfg.pollInterval)
+	}
+	if w.cfg.polling || err != nil {
+		n = newPollNotifier(regs, w.cfg.pollInterval)
+	}
+	defer n.Close()
+	w.readyOnce.Do(func() { close(w.ready) })
+
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-n.Changes():
+			timer.Reset(w.cfg.debounce)
+		case <-timer.C:
+			w.reload(ctx)
+		}
+	}
+}
+
+// reload registers files again and reloads the container.
+func (w *Watcher) reload(ctx context.Context) {
+	err := w.storage.Refresh()
+	if err == nil {
+		err = w.tc.Reload(ctx)
+	}
+	if w.cfg.onReload != nil {
+		w.cfg.onReload(err)
+	}
+}
+
+// relevantName reports whether a change of the file name in the
+// directory shall trigger reload. Names starting with ".." are used by
+// atomic writers, like Kubernetes ConfigMap volumes.
+func relevantName(regs []fileRegistration, dir, name string) bool {
+	if name == "" || strings.HasPrefix(name, "..") {
+		return true
+	}
+	for _, reg := range regs {
+		for _, p := range reg.paths {
+			if filepath.Clean(p) != filepath.Clean(dir) {
+				continue
+			}
+			if ok, _ := matchMask(reg.mask, name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pollNotifier detects changes by scanning directories periodically.
+type pollNotifier struct {
+	regs    []fileRegistration
+	changes chan struct{}
+	done    chan struct{}
+}
+
+func newPollNotifier(regs []fileRegistration, interval time.Duration) *pollNotifier {
+	n := pollNotifier{
+		regs:    regs,
+		changes: make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	// the first scan is done before return, so later changes are seen.
+	go n.run(interval, n.signature())
+	return &n
+}
+
+func (n *pollNotifier) run(interval time.Duration, last string) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-n.done:
+			return
+		case <-ticker.C:
+		}
+
+		if sig := n.signature(); sig != last {
+			last = sig
+			select {
+			case n.changes <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// signature describes names, sizes and modification times of the
+// watched files.
+func (n *pollNotifier) signature() string {
+	var lines []string
+	for _, reg := range n.regs {
+		for _, dir := range reg.paths {
+			des, err := os.ReadDir(dir)
+			if err != nil {
+				lines = append(lines, dir+":"+err.Error())
+				continue
+			}
+			for _, de := range des {
+				if !relevantName(n.regs, dir, de.Name()) {
+					continue
+				}
+				// os.Stat follows symlinks.
+				fi, err := os.Stat(filepath.Join(dir, de.Name()))
+				if err != nil {
+					continue
+				}
+				lines = append(lines, filepath.Join(dir, de.Name())+
+					":"+strconv.FormatInt(fi.Size(), 10)+
+					":"+strconv.FormatInt(fi.ModTime().UnixNano(), 10))
+			}
+		}
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+func (n *pollNotifier) Changes() <-chan struct{} {
+	return n.changes
+}
+
+func (n *pollNotifier) Close() error {
+	close(n.done)
+	return nil
+}
@@ -0,0 +1,191 @@
+//go:build linux
+
+package i18n
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+const inotifyMask = syscall.IN_CREATE | syscall.IN_DELETE | syscall.IN_MODIFY |
+	syscall.IN_CLOSE_WRITE | syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO |
+	syscall.IN_ATTRIB | syscall.IN_DELETE_SELF | syscall.IN_MOVE_SELF
+
+// inotifyNotifier detects changes by inotify.
+//
+// If a watched directory is deleted or moved away, its watch is lost.
+// The directory is polled until it reappears and watched again.
+type inotifyNotifier struct {
+	f        *os.File
+	fd       int
+	regs     []fileRegistration
+	interval time.Duration
+	changes  chan struct{}
+	done     chan struct{}
+
+	mu   sync.Mutex
+	dirs map[int32]string // watch descriptor -> directory
+}
+
+func newNativeNotifier(regs []fileRegistration, interval time.Duration) (notifier, error) {
+
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, os.NewSyscallError("inotify_init1", err)
+	}
+
+	n := inotifyNotifier{
+		// nonblocking descriptor is served by the runtime poller,
+		// so Close interrupts Read.
+		f:        os.NewFile(uintptr(fd), "inotify"),
+		fd:       fd,
+		regs:     regs,
+		interval: interval,
+		changes:  make(chan struct{}, 1),
+		done:     make(chan struct{}),
+		dirs:     make(map[int32]string),
+	}
+
+	for _, reg := range regs {
+		for _, dir := range reg.paths {
+			if err := n.addWatch(dir); err != nil {
+				n.f.Close()
+				return nil, err
+			}
+		}
+	}
+
+	go n.run()
+	return &n, nil
+}
+
+// addWatch adds the watch of the directory.
+func (n *inotifyNotifier) addWatch(dir string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	select {
+	case <-n.done:
+		return os.ErrClosed
+	default:
+	}
+
+	wd, err := syscall.InotifyAddWatch(n.fd, dir, inotifyMask)
+	if err != nil {
+		return os.NewSyscallError("inotify_add_watch", err)
+	}
+	n.dirs[int32(wd)] = filepath.Clean(dir)
+	return nil
+}
+
+// dir returns the directory of the watch descriptor.
+func (n *inotifyNotifier) dir(wd int32) string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.dirs[wd]
+}
+
+// lost forgets the removed watch and starts waiting for its directory.
+func (n *inotifyNotifier) lost(wd int32) {
+	n.mu.Lock()
+	dir, ok := n.dirs[wd]
+	delete(n.dirs, wd)
+	n.mu.Unlock()
+
+	if ok {
+		go n.rewatch(dir)
+	}
+}
+
+// rewatch polls the directory until it exists and watches it again.
+func (n *inotifyNotifier) rewatch(dir string) {
+	ticker := time.NewTicker(n.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-n.done:
+			return
+		case <-ticker.C:
+		}
+
+		err := n.addWatch(dir)
+		if errors.Is(err, os.ErrClosed) {
+			return
+		}
+		if err == nil {
+			// files might be created before the watch was added.
+			n.notify()
+			return
+		}
+	}
+}
+
+func (n *inotifyNotifier) run() {
+	buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+
+	for {
+		cnt, err := n.f.Read(buf)
+		if err != nil {
+			// os.ErrClosed after Close, other errors are not recoverable.
+			return
+		}
+
+		changed := false
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= cnt; {
+			ev := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			nameBytes := buf[offset+syscall.SizeofInotifyEvent : offset+syscall.SizeofInotifyEvent+int(ev.Len)]
+			offset += syscall.SizeofInotifyEvent + int(ev.Len)
+
+			name := string(nameBytes)
+			for i := 0; i < len(name); i++ {
+				if name[i] == 0 {
+					name = name[:i]
+					break
+				}
+			}
+
+			switch {
+			case ev.Mask&syscall.IN_IGNORED != 0:
+				// the watch was removed: the directory was deleted,
+				// moved away or its file system unmounted.
+				n.lost(ev.Wd)
+				changed = true
+			case ev.Mask&syscall.IN_MOVE_SELF != 0:
+				// the watch follows the moved directory, IN_IGNORED
+				// is delivered after removal.
+				syscall.InotifyRmWatch(n.fd, uint32(ev.Wd))
+				changed = true
+			case ev.Mask&syscall.IN_Q_OVERFLOW != 0 || relevantName(n.regs, n.dir(ev.Wd), name):
+				changed = true
+			}
+		}
+
+		if changed {
+			n.notify()
+		}
+	}
+}
+
+func (n *inotifyNotifier) notify() {
+	select {
+	case n.changes <- struct{}{}:
+	default:
+	}
+}
+
+func (n *inotifyNotifier) Changes() <-chan struct{} {
+	return n.changes
+}
+
+func (n *inotifyNotifier) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	close(n.done)
+	return n.f.Close()
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package i18n
+
+import (
+	"errors"
+	"time"
+)
+
+// newNativeNotifier is not implemented, polling is used instead.
+func newNativeNotifier(regs []fileRegistration, interval time.Duration) (notifier, error) {
+	return nil, errors.New("native file notifications are not supported")
+}
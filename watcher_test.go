@@ -0,0 +1,124 @@
+package i18n
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatcher(t *testing.T) {
+
+	tests := []struct {
+		name string
+		opts []WatcherOption
+	}{
+		{"native", []WatcherOption{WithPollInterval(10 * time.Millisecond)}},
+		{"polling", []WatcherOption{WithPolling(), WithPollInterval(10 * time.Millisecond)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "locales")
+			if err := os.Mkdir(dir, 0755); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			writeFile(t, filepath.Join(dir, "en.t18n"), "Save=Save\n")
+
+			r := NewLanguageRegistry()
+			en := r.Parse("en")
+			de := r.Parse("de")
+
+			fs := NewLocalFileStorage()
+			if err := fs.RegisterFiles("*.t18n", dir); err != nil {
+				t.Fatalf("RegisterFiles failed: %v", err)
+			}
+			tc := NewContainer(WithLanguageRegistry(r), WithStorage(fs))
+			if err := tc.ReadRegisteredFiles(); err != nil {
+				t.Fatalf("ReadRegisteredFiles failed: %v", err)
+			}
+
+			reloaded := make(chan error, 10)
+			opts := append([]WatcherOption{
+				WithDebounce(20 * time.Millisecond),
+				WithReloadCallback(func(err error) { reloaded <- err }),
+			}, tt.opts...)
+			w := NewWatcher(tc, fs, opts...)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error)
+			go func() { done <- w.Run(ctx) }()
+			defer func() {
+				cancel()
+				if err := <-done; err != nil {
+					t.Errorf("Run failed: %v", err)
+				}
+			}()
+
+			select {
+			case <-w.ready:
+			case <-time.After(5 * time.Second):
+				t.Fatalf("watcher start timeout")
+			}
+
+			// ignored file
+			writeFile(t, filepath.Join(dir, "notes.txt"), "x")
+			// changed and added files
+			writeFile(t, filepath.Join(dir, "en.t18n"), "Save=Store\n")
+			writeFile(t, filepath.Join(dir, "de.t18n"), "Save=Speichern\n")
+
+			waitReload(t, reloaded, func() bool {
+				return tc.Lang(en).Value("Save") == "Store" && tc.Lang(de).Value("Save") == "Speichern"
+			})
+
+			// removed file
+			if err := os.Remove(filepath.Join(dir, "de.t18n")); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			waitReload(t, reloaded, func() bool {
+				return tc.Lang(de).Value("Save") == "Save"
+			})
+
+			// removed and recreated directory
+			if err := os.RemoveAll(dir); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := os.Mkdir(dir, 0755); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			writeFile(t, filepath.Join(dir, "en.t18n"), "Save=Keep\n")
+			waitReload(t, reloaded, func() bool {
+				return tc.Lang(en).Value("Save") == "Keep"
+			})
+
+			// the directory is watched again
+			writeFile(t, filepath.Join(dir, "en.t18n"), "Save=Again\n")
+			waitReload(t, reloaded, func() bool {
+				return tc.Lang(en).Value("Save") == "Again"
+			})
+		})
+	}
+}
+
+func writeFile(t *testing.T, name, data string) {
+	t.Helper()
+	if err := os.WriteFile(name, []byte(data), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// waitReload waits for reloads until ok reports the expected state.
+// Failed reloads are expected while files are being changed.
+func waitReload(t *testing.T, reloaded chan error, ok func() bool) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	var last error
+	for !ok() {
+		select {
+		case last = <-reloaded:
+		case <-timeout:
+			t.Fatalf("reload timeout, last reload error: %v", last)
+		}
+	}
+}